@@ -0,0 +1,80 @@
+package forest
+
+import (
+	"context"
+	"time"
+)
+
+// ListAuthMethods returns every enabled auth method, keyed by path without trailing '/'
+func (v *Vault) ListAuthMethods(ctx context.Context) (methods map[string]Mount, err error) {
+	return v.listMounts(ctx, "/sys/auth/")
+}
+
+// EnableAuthMethod enables an auth method of type opts.Type, like "approle" or "kubernetes", at 'path'.
+// Version and Options of opts are ignored by most auth methods.
+//
+// Example: `err := vault.EnableAuthMethod(ctx, "approle", forest.MountOptions{Type: "approle"})`
+func (v *Vault) EnableAuthMethod(ctx context.Context, path string, opts MountOptions) (err error) {
+	return v.enableMount(ctx, "/sys/auth/", path, opts)
+}
+
+// TuneAuthMethod updates the configuration of the auth method at 'path'. Zero values of opts are left unchanged.
+func (v *Vault) TuneAuthMethod(ctx context.Context, path string, opts MountOptions) (err error) {
+	return v.tuneMount(ctx, "/sys/auth/", path, opts)
+}
+
+// ReadAuthMethodConfig returns the tunable configuration of the auth method at 'path'
+func (v *Vault) ReadAuthMethodConfig(ctx context.Context, path string) (c MountConfig, err error) {
+	return v.readMountConfig(ctx, "/sys/auth/", path)
+}
+
+// DisableAuthMethod disables the auth method at 'path'. Every token issued through it is revoked.
+func (v *Vault) DisableAuthMethod(ctx context.Context, path string) (err error) {
+	return v.disableMount(ctx, "/sys/auth/", path)
+}
+
+// EnsureAuthMethod makes sure an auth method of type opts.Type is enabled at 'path' with the given options.
+// Same as EnsureMount, it's safe to call on every start.
+func (v *Vault) EnsureAuthMethod(ctx context.Context, path string, opts MountOptions) (created bool, err error) {
+	return v.ensureMount(ctx, "/sys/auth/", path, opts)
+}
+
+// TokenSettings are the token parameters shared by roles of every auth method.
+// Zero values are not sent, so Vault's defaults apply.
+type TokenSettings struct {
+	TokenPolicies        []string
+	TokenTTL             time.Duration
+	TokenMaxTTL          time.Duration
+	TokenPeriod          time.Duration
+	TokenExplicitMaxTTL  time.Duration
+	TokenNumUses         int
+	TokenBoundCIDRs      []string
+	TokenNoDefaultPolicy bool
+	TokenType            string // "service", "batch", or one of the "default-" variants
+}
+
+type jsonTokenSettings struct {
+	TokenPolicies        []string `json:"token_policies,omitempty"`
+	TokenTTL             string   `json:"token_ttl,omitempty"`
+	TokenMaxTTL          string   `json:"token_max_ttl,omitempty"`
+	TokenPeriod          string   `json:"token_period,omitempty"`
+	TokenExplicitMaxTTL  string   `json:"token_explicit_max_ttl,omitempty"`
+	TokenNumUses         int      `json:"token_num_uses,omitempty"`
+	TokenBoundCIDRs      []string `json:"token_bound_cidrs,omitempty"`
+	TokenNoDefaultPolicy bool     `json:"token_no_default_policy,omitempty"`
+	TokenType            string   `json:"token_type,omitempty"`
+}
+
+func (t TokenSettings) toJSON() jsonTokenSettings {
+	return jsonTokenSettings{
+		TokenPolicies:        t.TokenPolicies,
+		TokenTTL:             optionalDuration(t.TokenTTL),
+		TokenMaxTTL:          optionalDuration(t.TokenMaxTTL),
+		TokenPeriod:          optionalDuration(t.TokenPeriod),
+		TokenExplicitMaxTTL:  optionalDuration(t.TokenExplicitMaxTTL),
+		TokenNumUses:         t.TokenNumUses,
+		TokenBoundCIDRs:      t.TokenBoundCIDRs,
+		TokenNoDefaultPolicy: t.TokenNoDefaultPolicy,
+		TokenType:            t.TokenType,
+	}
+}
@@ -0,0 +1,130 @@
+package forest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// AppRole is a role of the AppRole auth method, enabled at Config.AppRoleAuth
+type AppRole struct {
+	Name string
+	TokenSettings
+	BindSecretID       *bool // Require a secret ID to login. Defaults to true in Vault when nil.
+	SecretIDBoundCIDRs []string
+	SecretIDTTL        time.Duration
+	SecretIDNumUses    int // Zero allows unlimited uses
+	LocalSecretIDs     bool
+}
+
+type jsonAppRole struct {
+	jsonTokenSettings
+	BindSecretID       *bool    `json:"bind_secret_id,omitempty"`
+	SecretIDBoundCIDRs []string `json:"secret_id_bound_cidrs,omitempty"`
+	SecretIDTTL        string   `json:"secret_id_ttl,omitempty"`
+	SecretIDNumUses    int      `json:"secret_id_num_uses"`
+	LocalSecretIDs     bool     `json:"local_secret_ids,omitempty"`
+}
+
+// AppRoleSecretID is a newly generated secret ID of an AppRole
+type AppRoleSecretID struct {
+	SecretID         string `json:"secret_id"`
+	SecretIDAccessor string `json:"secret_id_accessor"`
+	SecretIDTTL      int64  `json:"secret_id_ttl"` // Seconds. Zero means no expiration.
+	SecretIDNumUses  int    `json:"secret_id_num_uses"`
+}
+
+type appRoleSecretIDResponse struct {
+	RequestID     string          `json:"request_id"`
+	LeaseID       string          `json:"lease_id"`
+	Renewable     bool            `json:"renewable"`
+	LeaseDuration int             `json:"lease_duration"`
+	Data          AppRoleSecretID `json:"data"`
+}
+
+type appRoleIDResponse struct {
+	RequestID     string `json:"request_id"`
+	LeaseID       string `json:"lease_id"`
+	Renewable     bool   `json:"renewable"`
+	LeaseDuration int    `json:"lease_duration"`
+	Data          struct {
+		RoleID string `json:"role_id"`
+	} `json:"data"`
+}
+
+func (v *Vault) appRolePath(name string) (string, error) {
+	if name == "" {
+		return "", errors.New("AppRole name cannot be empty")
+	}
+	return fmt.Sprintf("/auth/%s/role/%s", v.Config.AppRoleAuth, url.PathEscape(name)), nil
+}
+
+// UpsertAppRole creates/updates AppRole 'role.Name'
+//
+// Example: `err := vault.UpsertAppRole(ctx, forest.AppRole{Name: "ms-order", TokenSettings: forest.TokenSettings{TokenPolicies: []string{"ms-order"}}})`
+func (v *Vault) UpsertAppRole(ctx context.Context, role AppRole) (err error) {
+	path, err := v.appRolePath(role.Name)
+	if err != nil {
+		return
+	}
+	body := jsonAppRole{
+		jsonTokenSettings:  role.toJSON(),
+		BindSecretID:       role.BindSecretID,
+		SecretIDBoundCIDRs: role.SecretIDBoundCIDRs,
+		SecretIDTTL:        optionalDuration(role.SecretIDTTL),
+		SecretIDNumUses:    role.SecretIDNumUses,
+		LocalSecretIDs:     role.LocalSecretIDs,
+	}
+	return v.doJSON(ctx, http.MethodPost, path, body, nil)
+}
+
+// ListAppRoles returns names of every AppRole
+func (v *Vault) ListAppRoles(ctx context.Context) (names []string, err error) {
+	var ddd configList
+	err = v.doJSON(ctx, http.MethodGet, fmt.Sprintf("/auth/%s/role?list=true", v.Config.AppRoleAuth), nil, &ddd)
+	return ddd.Data.Keys, err
+}
+
+// DeleteAppRole deletes AppRole 'name'. Secret IDs generated for it are deleted too.
+func (v *Vault) DeleteAppRole(ctx context.Context, name string) (err error) {
+	path, err := v.appRolePath(name)
+	if err != nil {
+		return
+	}
+	return v.doJSON(ctx, http.MethodDelete, path, nil, nil)
+}
+
+// AppRoleID returns the role ID of AppRole 'name', which services use with a secret ID to login
+func (v *Vault) AppRoleID(ctx context.Context, name string) (roleID string, err error) {
+	path, err := v.appRolePath(name)
+	if err != nil {
+		return
+	}
+	var response appRoleIDResponse
+	err = v.doJSON(ctx, http.MethodGet, path+"/role-id", nil, &response)
+	return response.Data.RoleID, err
+}
+
+// GenerateAppRoleSecretID generates a new secret ID for AppRole 'name'. 'metadata' is attached to tokens issued with it.
+func (v *Vault) GenerateAppRoleSecretID(ctx context.Context, name string, metadata map[string]string) (s AppRoleSecretID, err error) {
+	path, err := v.appRolePath(name)
+	if err != nil {
+		return
+	}
+	body := map[string]string{}
+	if len(metadata) > 0 {
+		// Vault expects metadata as a JSON encoded string
+		meta, err := json.Marshal(metadata)
+		if err != nil {
+			return s, err
+		}
+		body["metadata"] = string(meta)
+	}
+	var response appRoleSecretIDResponse
+	err = v.doJSON(ctx, http.MethodPost, path+"/secret-id", body, &response)
+	return response.Data, err
+}
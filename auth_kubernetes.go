@@ -0,0 +1,106 @@
+package forest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// KubernetesAuthConfig configures the Kubernetes auth method enabled at Config.KubernetesAuth
+type KubernetesAuthConfig struct {
+	Host                 string // Kubernetes API address, like https://kubernetes.default.svc:443
+	CACert               string // PEM encoded CA of the Kubernetes API. Empty uses the local CA when Vault runs in the cluster.
+	TokenReviewerJWT     string // Empty uses the local service account token when Vault runs in the cluster
+	Issuer               string
+	DisableISSValidation bool
+	DisableLocalCAJWT    bool
+}
+
+type jsonKubernetesAuthConfig struct {
+	Host                 string `json:"kubernetes_host"`
+	CACert               string `json:"kubernetes_ca_cert,omitempty"`
+	TokenReviewerJWT     string `json:"token_reviewer_jwt,omitempty"`
+	Issuer               string `json:"issuer,omitempty"`
+	DisableISSValidation bool   `json:"disable_iss_validation,omitempty"`
+	DisableLocalCAJWT    bool   `json:"disable_local_ca_jwt,omitempty"`
+}
+
+// KubernetesRole is a role of the Kubernetes auth method, binding service accounts to token settings
+type KubernetesRole struct {
+	Name string
+	TokenSettings
+	BoundServiceAccountNames      []string // Required. "*" allows any name.
+	BoundServiceAccountNamespaces []string // Required. "*" allows any namespace.
+	Audience                      string
+	AliasNameSource               string // "serviceaccount_uid" or "serviceaccount_name"
+}
+
+type jsonKubernetesRole struct {
+	jsonTokenSettings
+	BoundServiceAccountNames      []string `json:"bound_service_account_names"`
+	BoundServiceAccountNamespaces []string `json:"bound_service_account_namespaces"`
+	Audience                      string   `json:"audience,omitempty"`
+	AliasNameSource               string   `json:"alias_name_source,omitempty"`
+}
+
+// ConfigureKubernetesAuth writes the configuration of the Kubernetes auth method
+func (v *Vault) ConfigureKubernetesAuth(ctx context.Context, c KubernetesAuthConfig) (err error) {
+	if c.Host == "" {
+		return errors.New("kubernetes host cannot be empty")
+	}
+	body := jsonKubernetesAuthConfig(c)
+	return v.doJSON(ctx, http.MethodPost, fmt.Sprintf("/auth/%s/config", v.Config.KubernetesAuth), body, nil)
+}
+
+func (v *Vault) kubernetesRolePath(name string) (string, error) {
+	if name == "" {
+		return "", errors.New("kubernetes role name cannot be empty")
+	}
+	return fmt.Sprintf("/auth/%s/role/%s", v.Config.KubernetesAuth, url.PathEscape(name)), nil
+}
+
+// UpsertKubernetesRole creates/updates Kubernetes auth role 'role.Name'
+//
+// Example:
+//
+//	err := vault.UpsertKubernetesRole(ctx, forest.KubernetesRole{
+//		Name:                          "ms-order",
+//		BoundServiceAccountNames:      []string{"ms-order"},
+//		BoundServiceAccountNamespaces: []string{"production"},
+//		TokenSettings:                 forest.TokenSettings{TokenPolicies: []string{"ms-order"}},
+//	})
+func (v *Vault) UpsertKubernetesRole(ctx context.Context, role KubernetesRole) (err error) {
+	path, err := v.kubernetesRolePath(role.Name)
+	if err != nil {
+		return
+	}
+	if len(role.BoundServiceAccountNames) == 0 || len(role.BoundServiceAccountNamespaces) == 0 {
+		return errors.New("kubernetes role needs bound service account names and namespaces")
+	}
+	body := jsonKubernetesRole{
+		jsonTokenSettings:             role.toJSON(),
+		BoundServiceAccountNames:      role.BoundServiceAccountNames,
+		BoundServiceAccountNamespaces: role.BoundServiceAccountNamespaces,
+		Audience:                      role.Audience,
+		AliasNameSource:               role.AliasNameSource,
+	}
+	return v.doJSON(ctx, http.MethodPost, path, body, nil)
+}
+
+// ListKubernetesRoles returns names of every Kubernetes auth role
+func (v *Vault) ListKubernetesRoles(ctx context.Context) (names []string, err error) {
+	var ddd configList
+	err = v.doJSON(ctx, http.MethodGet, fmt.Sprintf("/auth/%s/role?list=true", v.Config.KubernetesAuth), nil, &ddd)
+	return ddd.Data.Keys, err
+}
+
+// DeleteKubernetesRole deletes Kubernetes auth role 'name'
+func (v *Vault) DeleteKubernetesRole(ctx context.Context, name string) (err error) {
+	path, err := v.kubernetesRolePath(name)
+	if err != nil {
+		return
+	}
+	return v.doJSON(ctx, http.MethodDelete, path, nil, nil)
+}
@@ -0,0 +1,101 @@
+package forest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TokenSettings_JSON(t *testing.T) {
+	b, err := json.Marshal(jsonAppRole{
+		jsonTokenSettings: TokenSettings{TokenPolicies: []string{"ms-order"}, TokenTTL: 90 * time.Minute}.toJSON(),
+		SecretIDTTL:       optionalDuration(time.Hour),
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"token_policies":["ms-order"],"token_ttl":"1h30m","secret_id_ttl":"1h","secret_id_num_uses":0}`, string(b))
+}
+
+func Test_AuthMethods(t *testing.T) {
+	ctx := context.TODO()
+	v, err := NewClient(*testToken, WithHost(*testHost), WithAppRoleAuth("forest_approle"), WithKubernetesAuth("forest_kubernetes"))
+	require.NoError(t, err)
+	v.DisableAuthMethod(ctx, "forest_approle")
+	v.DisableAuthMethod(ctx, "forest_kubernetes")
+	defer v.DisableAuthMethod(ctx, "forest_approle")
+	defer v.DisableAuthMethod(ctx, "forest_kubernetes")
+
+	t.Run("Enable", func(t *testing.T) {
+		created, err := v.EnsureAuthMethod(ctx, "forest_approle", MountOptions{Type: "approle", Description: "forest test"})
+		require.NoError(t, err)
+		assert.True(t, created)
+		created, err = v.EnsureAuthMethod(ctx, "forest_approle", MountOptions{Type: "approle"})
+		require.NoError(t, err)
+		assert.False(t, created)
+		require.NoError(t, v.EnableAuthMethod(ctx, "forest_kubernetes", MountOptions{Type: "kubernetes"}))
+
+		methods, err := v.ListAuthMethods(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "approle", methods["forest_approle"].Type)
+		assert.Equal(t, "forest test", methods["forest_approle"].Description)
+		assert.Equal(t, "kubernetes", methods["forest_kubernetes"].Type)
+	})
+
+	t.Run("Tune", func(t *testing.T) {
+		require.NoError(t, v.TuneAuthMethod(ctx, "forest_approle", MountOptions{DefaultLeaseTTL: 30 * time.Minute}))
+		c, err := v.ReadAuthMethodConfig(ctx, "forest_approle")
+		require.NoError(t, err)
+		assert.Equal(t, 30*time.Minute, c.DefaultLeaseTTL)
+	})
+
+	t.Run("AppRole", func(t *testing.T) {
+		err := v.UpsertAppRole(ctx, AppRole{
+			Name:            "ms-order",
+			TokenSettings:   TokenSettings{TokenPolicies: []string{"default"}, TokenTTL: 10 * time.Minute},
+			SecretIDTTL:     time.Hour,
+			SecretIDNumUses: 1,
+		})
+		require.NoError(t, err)
+		roles, err := v.ListAppRoles(ctx)
+		require.NoError(t, err)
+		assert.Contains(t, roles, "ms-order")
+
+		roleID, err := v.AppRoleID(ctx, "ms-order")
+		require.NoError(t, err)
+		secret, err := v.GenerateAppRoleSecretID(ctx, "ms-order", map[string]string{"service": "ms-order"})
+		require.NoError(t, err)
+		assert.NotEmpty(t, secret.SecretID)
+		assert.Equal(t, int64(3600), secret.SecretIDTTL)
+
+		var login tokenResponse
+		err = v.doJSON(ctx, http.MethodPost, "/auth/forest_approle/login", map[string]string{"role_id": roleID, "secret_id": secret.SecretID}, &login)
+		require.NoError(t, err)
+		assert.Contains(t, login.Auth.TokenPolicies, "default")
+		assert.Equal(t, "ms-order", login.Auth.Metadata["service"])
+		assert.Equal(t, 10*time.Minute, login.Auth.LeaseTTL())
+
+		require.NoError(t, v.DeleteAppRole(ctx, "ms-order"))
+	})
+
+	t.Run("Kubernetes", func(t *testing.T) {
+		err := v.ConfigureKubernetesAuth(ctx, KubernetesAuthConfig{Host: "https://kubernetes.default.svc", DisableLocalCAJWT: true})
+		require.NoError(t, err)
+		err = v.UpsertKubernetesRole(ctx, KubernetesRole{Name: "ms-order"})
+		assert.Error(t, err)
+		err = v.UpsertKubernetesRole(ctx, KubernetesRole{
+			Name:                          "ms-order",
+			BoundServiceAccountNames:      []string{"ms-order"},
+			BoundServiceAccountNamespaces: []string{"production"},
+			TokenSettings:                 TokenSettings{TokenPolicies: []string{"default"}},
+		})
+		require.NoError(t, err)
+		roles, err := v.ListKubernetesRoles(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ms-order"}, roles)
+		require.NoError(t, v.DeleteKubernetesRole(ctx, "ms-order"))
+	})
+}
@@ -0,0 +1,105 @@
+package forest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// CapabilityRoot is returned by capability checks when the token has the root policy
+const CapabilityRoot Capability = "root"
+
+// CapabilityMap maps a path to the capabilities a token has on it
+type CapabilityMap map[string][]Capability
+
+// Can reports whether 'c' is allowed on 'path'. Deny wins over everything, and root allows everything else.
+// Paths which were not checked are never allowed.
+func (m CapabilityMap) Can(path string, c Capability) bool {
+	caps, ok := m[path]
+	if !ok {
+		return false
+	}
+	allowed := false
+	for _, have := range caps {
+		if have == CapabilityDeny {
+			return c == CapabilityDeny
+		}
+		if have == c || have == CapabilityRoot {
+			allowed = true
+		}
+	}
+	return allowed
+}
+
+// Missing returns the capabilities of 'required' which are not allowed, keyed by path. Empty if all are allowed.
+//
+// Example: `missing := caps.Missing(forest.CapabilityMap{"kv/ms-order-conf": {forest.CapabilityRead}})`
+func (m CapabilityMap) Missing(required CapabilityMap) CapabilityMap {
+	missing := make(CapabilityMap)
+	for path, caps := range required {
+		for _, c := range caps {
+			if !m.Can(path, c) {
+				missing[path] = append(missing[path], c)
+			}
+		}
+	}
+	return missing
+}
+
+type capabilitiesRequest struct {
+	Token    string   `json:"token,omitempty"`
+	Accessor string   `json:"accessor,omitempty"`
+	Paths    []string `json:"paths"`
+}
+
+type capabilitiesResponse struct {
+	RequestID     string                  `json:"request_id"`
+	LeaseID       string                  `json:"lease_id"`
+	Renewable     bool                    `json:"renewable"`
+	LeaseDuration int                     `json:"lease_duration"`
+	WrapInfo      interface{}             `json:"wrap_info"`
+	Warnings      []string                `json:"warnings"`
+	Data          map[string][]Capability `json:"data"`
+}
+
+func (v *Vault) capabilities(ctx context.Context, path string, body capabilitiesRequest) (CapabilityMap, error) {
+	if len(body.Paths) == 0 {
+		return nil, errors.New("at least one path is required")
+	}
+	var response capabilitiesResponse
+	if err := v.doJSON(ctx, http.MethodPost, path, body, &response); err != nil {
+		return nil, err
+	}
+	// Vault also returns a 'capabilities' key mirroring the first path, which is not a path unless asked for
+	m := make(CapabilityMap, len(body.Paths))
+	for _, p := range body.Paths {
+		m[p] = response.Data[p]
+	}
+	return m, nil
+}
+
+// CapabilitiesSelf returns the capabilities the instance token has on each of 'paths'.
+// Use it to check a job can do everything it needs before it starts.
+//
+// Example: `caps, err := vault.CapabilitiesSelf(ctx, "kv/ms-order-conf", "transit/encrypt/pii")`
+func (v *Vault) CapabilitiesSelf(ctx context.Context, paths ...string) (CapabilityMap, error) {
+	return v.capabilities(ctx, "/"+PathSysCapabilitiesSelf, capabilitiesRequest{Paths: paths})
+}
+
+// Capabilities returns the capabilities 'token' has on each of 'paths'.
+// Instance token needs update permission on sys/capabilities.
+func (v *Vault) Capabilities(ctx context.Context, token string, paths ...string) (CapabilityMap, error) {
+	if token == "" {
+		return nil, errors.New("Empty token")
+	}
+	return v.capabilities(ctx, "/sys/capabilities", capabilitiesRequest{Token: token, Paths: paths})
+}
+
+// CapabilitiesAccessor returns the capabilities the token of 'accessor' has on each of 'paths'.
+// Instance token needs update permission on sys/capabilities-accessor.
+func (v *Vault) CapabilitiesAccessor(ctx context.Context, accessor string, paths ...string) (CapabilityMap, error) {
+	if accessor == "" {
+		return nil, errors.New("Empty accessor")
+	}
+	return v.capabilities(ctx, "/sys/capabilities-accessor", capabilitiesRequest{Accessor: accessor, Paths: paths})
+}
@@ -0,0 +1,87 @@
+package forest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CapabilityMap(t *testing.T) {
+	m := CapabilityMap{
+		"kv/ms-order-conf":    {CapabilityRead, CapabilityList},
+		"transit/encrypt/pii": {CapabilityDeny},
+		"sys/mounts":          {CapabilityRoot},
+	}
+	assert.True(t, m.Can("kv/ms-order-conf", CapabilityRead))
+	assert.False(t, m.Can("kv/ms-order-conf", CapabilityUpdate))
+	assert.False(t, m.Can("transit/encrypt/pii", CapabilityUpdate))
+	assert.True(t, m.Can("transit/encrypt/pii", CapabilityDeny))
+	assert.True(t, m.Can("sys/mounts", CapabilitySudo))
+	assert.False(t, m.Can("unknown", CapabilityRead))
+
+	missing := m.Missing(CapabilityMap{
+		"kv/ms-order-conf":    {CapabilityRead, CapabilityUpdate},
+		"transit/encrypt/pii": {CapabilityUpdate},
+		"sys/mounts":          {CapabilityRead},
+	})
+	assert.Equal(t, CapabilityMap{
+		"kv/ms-order-conf":    {CapabilityUpdate},
+		"transit/encrypt/pii": {CapabilityUpdate},
+	}, missing)
+}
+
+func Test_Capabilities(t *testing.T) {
+	err := UpsertPolicyModel(context.TODO(), Policy{
+		Name: "test-capabilities",
+		Paths: []PathRule{
+			{Path: "forest_kv/forest-data", Capabilities: []Capability{CapabilityRead}},
+			{Path: "forest_transit_test/encrypt/aes", Capabilities: []Capability{CapabilityDeny}},
+		},
+	})
+	require.NoError(t, err)
+	token, err := CreateNewToken().WithPolicies("test-capabilities").WithTimeToLive(10 * time.Minute).Do(context.TODO())
+	require.NoError(t, err)
+	defer RevokeToken(context.TODO(), token)
+	paths := []string{"forest_kv/forest-data", "forest_transit_test/encrypt/aes"}
+
+	check := func(t *testing.T, caps CapabilityMap) {
+		assert.Len(t, caps, 2)
+		assert.True(t, caps.Can("forest_kv/forest-data", CapabilityRead))
+		assert.False(t, caps.Can("forest_kv/forest-data", CapabilityUpdate))
+		assert.False(t, caps.Can("forest_transit_test/encrypt/aes", CapabilityUpdate))
+	}
+
+	t.Run("Self", func(t *testing.T) {
+		caps, err := CapabilitiesSelf(ContextWithToken(context.TODO(), token), paths...)
+		require.NoError(t, err)
+		check(t, caps)
+	})
+
+	t.Run("Self Root", func(t *testing.T) {
+		caps, err := CapabilitiesSelf(context.TODO(), "forest_kv/forest-data")
+		require.NoError(t, err)
+		assert.True(t, caps.Can("forest_kv/forest-data", CapabilityUpdate))
+	})
+
+	t.Run("Token", func(t *testing.T) {
+		caps, err := Capabilities(context.TODO(), token, paths...)
+		require.NoError(t, err)
+		check(t, caps)
+	})
+
+	t.Run("Accessor", func(t *testing.T) {
+		look, err := LookupOther(context.TODO(), token)
+		require.NoError(t, err)
+		caps, err := CapabilitiesAccessor(context.TODO(), look.Data.Accessor, paths...)
+		require.NoError(t, err)
+		check(t, caps)
+	})
+
+	t.Run("No Path", func(t *testing.T) {
+		_, err := CapabilitiesSelf(context.TODO())
+		assert.Error(t, err)
+	})
+}
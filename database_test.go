@@ -0,0 +1,143 @@
+package forest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// databaseServer fakes the database engine, since the test Vault has no database to manage.
+// Each credentials request issues a new non renewable user valid for one second, or with renewable set,
+// a renewable user valid for two seconds whose renewals always fail.
+func databaseServer(t *testing.T, renewable bool) (*Vault, *int32, *httptest.Server) {
+	var issued int32
+	duration := 1
+	if renewable {
+		duration = 2
+	}
+	v, server := fakeVault(t, map[string]http.HandlerFunc{
+		"/forest_database/creds/ms-order": func(w http.ResponseWriter, r *http.Request) {
+			n := atomic.AddInt32(&issued, 1)
+			fmt.Fprintf(w, `{"lease_id":"forest_database/creds/ms-order/%d","renewable":%t,"lease_duration":%d,
+				"data":{"username":"v-ms-order-%d","password":"p@ss/%d"}}`, n, renewable, duration, n, n)
+		},
+		"/sys/leases/renew": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"errors":["connection to database refused"]}`))
+		},
+		"/forest_database/static-creds/ms-order": func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"data":{"username":"order","password":"secret","last_vault_rotation":"2020-01-01T00:00:00Z",
+				"rotation_period":86400,"ttl":3600}}`))
+		},
+		"/forest_database/roles/ms-order": func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			assert.Equal(t, map[string]interface{}{
+				"db_name": "orders", "creation_statements": []interface{}{"CREATE ROLE"}, "default_ttl": "1h",
+			}, body)
+			w.WriteHeader(http.StatusNoContent)
+		},
+	}, WithDatabaseEngine("forest_database"))
+	return v, &issued, server
+}
+
+func Test_DatabaseCredentials(t *testing.T) {
+	ctx := context.TODO()
+	v, _, server := databaseServer(t, false)
+	defer server.Close()
+
+	creds, err := v.GetDatabaseCredentials(ctx, "ms-order")
+	require.NoError(t, err)
+	assert.Equal(t, DatabaseCredentials{
+		Username: "v-ms-order-1",
+		Password: "p@ss/1",
+		Lease:    LeaseInfo{LeaseID: "forest_database/creds/ms-order/1", LeaseDuration: 1},
+	}, creds)
+
+	static, err := v.GetDatabaseStaticCredentials(ctx, "ms-order")
+	require.NoError(t, err)
+	assert.Equal(t, "order", static.Username)
+	assert.Equal(t, 24*time.Hour, static.RotationPeriod)
+	assert.Equal(t, time.Hour, static.TTL)
+	assert.Equal(t, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), static.LastRotation)
+
+	err = v.UpsertDatabaseRole(ctx, DatabaseRole{Name: "ms-order", DBName: "orders", CreationStatements: []string{"CREATE ROLE"}, DefaultTTL: time.Hour})
+	require.NoError(t, err)
+	assert.Error(t, v.UpsertDatabaseRole(ctx, DatabaseRole{Name: "ms-order"}))
+	assert.Error(t, v.UpsertDatabaseStaticRole(ctx, DatabaseStaticRole{Name: "ms-order", DBName: "orders", Username: "order", RotationPeriod: time.Second}))
+	assert.Error(t, v.UpsertDatabaseConnection(ctx, DatabaseConnection{Name: "orders"}))
+	_, err = v.GetDatabaseCredentials(ctx, "")
+	assert.Error(t, err)
+}
+
+func Test_DSNBuilders(t *testing.T) {
+	creds := DatabaseCredentials{Username: "v-ms-order", Password: "p@ss/word"}
+	assert.Equal(t, "postgres://v-ms-order:p%40ss%2Fword@db:5432/orders?sslmode=require", PostgresDSN("db:5432", "orders", "sslmode=require")(creds))
+	assert.Equal(t, "v-ms-order:p@ss/word@tcp(db:3306)/orders?parseTime=true", MySQLDSN("db:3306", "orders", "parseTime=true")(creds))
+	assert.Equal(t, "v-ms-order:p@ss/word@tcp(db:3306)/orders", MySQLDSN("db:3306", "orders", "")(creds))
+}
+
+func Test_WatchDatabaseDSN(t *testing.T) {
+	v, issued, server := databaseServer(t, false)
+	defer server.Close()
+	ctx, cancel := context.WithCancel(context.TODO())
+	changes := make(chan string, 4)
+	d, err := v.WatchDatabaseDSN(ctx, "ms-order", MySQLDSN("db:3306", "orders", ""), DatabaseDSNOptions{
+		Watcher:  LeaseWatcherOptions{RenewFraction: 0.1},
+		OnChange: func(dsn string) { changes <- dsn },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "v-ms-order-1:p@ss/1@tcp(db:3306)/orders", d.DSN())
+
+	select {
+	case dsn := <-changes:
+		assert.Equal(t, "v-ms-order-2:p@ss/2@tcp(db:3306)/orders", dsn)
+		assert.Equal(t, dsn, d.DSN())
+		assert.Equal(t, "v-ms-order-2", d.Credentials().Username)
+	case <-time.After(2 * time.Second):
+		t.Fatal("DSN was not refreshed before the lease expired")
+	}
+
+	cancel()
+	<-d.Done()
+	// Credentials that are not renewable are still kept for RenewFraction of their TTL
+	assert.GreaterOrEqual(t, atomic.LoadInt32(issued), int32(2))
+	assert.LessOrEqual(t, atomic.LoadInt32(issued), int32(3))
+}
+
+func Test_WatchDatabaseDSN_RenewFails(t *testing.T) {
+	v, _, server := databaseServer(t, true)
+	defer server.Close()
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	changes := make(chan string, 4)
+	var failures int32
+	d, err := v.WatchDatabaseDSN(ctx, "ms-order", MySQLDSN("db:3306", "orders", ""), DatabaseDSNOptions{
+		Watcher:  LeaseWatcherOptions{RenewFraction: 0.1, RetryInterval: 500 * time.Millisecond},
+		OnChange: func(dsn string) { changes <- dsn },
+		OnError:  func(error) { atomic.AddInt32(&failures, 1) },
+	})
+	require.NoError(t, err)
+	expiresAt := time.Now().Add(2 * time.Second)
+
+	// New credentials are issued once renewing is given up, before Vault revokes the current ones
+	select {
+	case dsn := <-changes:
+		assert.True(t, time.Now().Before(expiresAt), "DSN refreshed after the lease expired")
+		assert.Equal(t, "v-ms-order-2:p@ss/2@tcp(db:3306)/orders", dsn)
+	case <-time.After(3 * time.Second):
+		t.Fatal("DSN was not refreshed")
+	}
+	assert.GreaterOrEqual(t, atomic.LoadInt32(&failures), int32(1))
+
+	cancel()
+	<-d.Done()
+}
@@ -1,7 +1,9 @@
 package forest
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -19,6 +21,10 @@ var (
 	defaultHTTPClient     = http.DefaultClient
 	defaultTransitEngine  = "transit"
 	defaultKeyValueEngine = "kv"
+	defaultDatabaseEngine = "database"
+	defaultPKIEngine      = "pki"
+	defaultAppRoleAuth    = "approle"
+	defaultKubernetesAuth = "kubernetes"
 )
 
 // NewClient creates a new vault instance
@@ -33,6 +39,10 @@ func NewClient(token string, opts ...OptionFunc) (*Vault, error) {
 		VaultAPIVersion: V1,
 		KeyValueEngine:  defaultKeyValueEngine,
 		TransitEngine:   defaultTransitEngine,
+		DatabaseEngine:  defaultDatabaseEngine,
+		PKIEngine:       defaultPKIEngine,
+		AppRoleAuth:     defaultAppRoleAuth,
+		KubernetesAuth:  defaultKubernetesAuth,
 	}
 
 	for _, opt := range opts {
@@ -47,6 +57,16 @@ func NewClient(token string, opts ...OptionFunc) (*Vault, error) {
 	return z, nil
 }
 
+// WithToken returns a lightweight copy of the instance authenticating as 'token'.
+// The copy shares the same http client and configuration, so it's cheap to create per request.
+//
+// Example: `data, err := vault.WithToken(downstreamToken).GetKeyValue(ctx, "ms-order-conf")`
+func (v *Vault) WithToken(token string) *Vault {
+	derived := *v
+	derived.Config.Token = token
+	return &derived
+}
+
 // GetKVEngine returns the engine name used for the instance
 func (v *Vault) GetKVEngine() string {
 	return v.Config.KeyValueEngine
@@ -57,17 +77,32 @@ func (v *Vault) GetTransitEngine() string {
 	return v.Config.TransitEngine
 }
 
+type contextKey int
+
+const tokenContextKey contextKey = iota
+
+// ContextWithToken returns a copy of ctx which makes any method called with it authenticate as 'token'
+// instead of the instance token. Works for every method, including the global ones.
+//
+// Example: `data, err := forest.GetKeyValue(forest.ContextWithToken(ctx, downstreamToken), "ms-order-conf")`
+func ContextWithToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, tokenContextKey, token)
+}
+
+func tokenFromContext(ctx context.Context) (token string, ok bool) {
+	token, ok = ctx.Value(tokenContextKey).(string)
+	return token, ok && token != ""
+}
+
 func (v *Vault) requestGen(ctx context.Context, method string, path string, body io.Reader) (req *http.Request, err error) {
-	req, err = http.NewRequestWithContext(ctx, method, v.BaseURL+path, body)
-	if err != nil {
-		return nil, err
+	token := v.Config.Token
+	if t, ok := tokenFromContext(ctx); ok {
+		token = t
 	}
-	req.Header.Set("X-Vault-Token", v.Config.Token)
-	req.Header.Set("X-Vault-Request", "true")
-	req.Header.Set("Content-Type", "application/json")
-	return
+	return v.requestGenOverride(ctx, method, path, token, body)
 }
 
+// requestGenOverride generates request authenticated as 'token', ignoring both instance and context token
 func (v *Vault) requestGenOverride(ctx context.Context, method, path, token string, body io.Reader) (req *http.Request, err error) {
 	req, err = http.NewRequestWithContext(ctx, method, v.BaseURL+path, body)
 	if err != nil {
@@ -78,3 +113,42 @@ func (v *Vault) requestGenOverride(ctx context.Context, method, path, token stri
 	req.Header.Set("Content-Type", "application/json")
 	return
 }
+
+// send sends the request with the instance http client and checks for error response.
+// Response body must be closed by the caller if err is nil.
+func (v *Vault) send(req *http.Request) (res *http.Response, err error) {
+	res, err = v.Config.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if err = checkErrorResponse(res); err != nil {
+		res.Body.Close()
+		return nil, err
+	}
+	return res, nil
+}
+
+// doJSON sends 'in' as JSON body if not nil, and decodes the response into 'out' if not nil
+func (v *Vault) doJSON(ctx context.Context, method, path string, in, out interface{}) (err error) {
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewBuffer(b)
+	}
+	req, err := v.requestGen(ctx, method, path, body)
+	if err != nil {
+		return
+	}
+	res, err := v.send(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+	if out == nil {
+		return
+	}
+	return json.NewDecoder(res.Body).Decode(out)
+}
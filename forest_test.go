@@ -3,6 +3,9 @@ package forest
 import (
 	"flag"
 	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -16,8 +19,29 @@ func init() {
 	}()
 	flag.Parse()
 	err := Init(
-		*testToken, WithHost(*testHost), WithTransitEngine("forest_transit_test"), WithKeyValueEngine("forest_kv"))
+		*testToken, WithHost(*testHost), WithTransitEngine("forest_transit_test"), WithKeyValueEngine("forest_kv"),
+		WithPKIEngine("forest_pki"))
 	if err != nil {
 		log.Fatal(err)
 	}
 }
+
+// fakeVault serves 'routes', keyed by API path without the /v1 prefix, and answers anything else with Vault's 404.
+// Only for what the test Vault can't provide, like database plugins or unhealthy states. Close the server when done.
+func fakeVault(t testing.TB, routes map[string]http.HandlerFunc, opts ...OptionFunc) (*Vault, *httptest.Server) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler, ok := routes[strings.TrimPrefix(r.URL.Path, "/v1")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[]}`))
+			return
+		}
+		handler(w, r)
+	}))
+	v, err := NewClient("x", append([]OptionFunc{WithHost(server.URL)}, opts...)...)
+	if err != nil {
+		server.Close()
+		t.Fatal(err)
+	}
+	return v, server
+}
@@ -2,9 +2,14 @@ package forest
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"time"
 )
 
 var gVault *Vault
@@ -21,6 +26,10 @@ func Init(token string, opts ...OptionFunc) (err error) {
 		VaultAPIVersion: V1,
 		KeyValueEngine:  defaultKeyValueEngine,
 		TransitEngine:   defaultTransitEngine,
+		DatabaseEngine:  defaultDatabaseEngine,
+		PKIEngine:       defaultPKIEngine,
+		AppRoleAuth:     defaultAppRoleAuth,
+		KubernetesAuth:  defaultKubernetesAuth,
 	}
 
 	for _, opt := range opts {
@@ -122,12 +131,54 @@ func RenewTokenOverride(ctx context.Context, token string) (err error) {
 	return gVault.RenewTokenOverride(ctx, token)
 }
 
+// RenewTokenSelfIncrement attempts to renew token registered to self, asking for 'increment' more time to live.
+// Returns the auth block with what Vault actually granted.
+func RenewTokenSelfIncrement(ctx context.Context, increment time.Duration) (auth TokenAuth, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.RenewTokenSelfIncrement(ctx, increment)
+}
+
+// RenewTokenOtherIncrement is RenewTokenOther asking for 'increment' more time to live
+func RenewTokenOtherIncrement(ctx context.Context, token string, increment time.Duration) (auth TokenAuth, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.RenewTokenOtherIncrement(ctx, token, increment)
+}
+
+// RenewTokenOverrideIncrement is RenewTokenOverride asking for 'increment' more time to live
+func RenewTokenOverrideIncrement(ctx context.Context, token string, increment time.Duration) (auth TokenAuth, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.RenewTokenOverrideIncrement(ctx, token, increment)
+}
+
+// RenewTokenAccessorIncrement is RenewTokenAccessor asking for 'increment' more time to live
+func RenewTokenAccessorIncrement(ctx context.Context, accessor string, increment time.Duration) (auth TokenAuth, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.RenewTokenAccessorIncrement(ctx, accessor, increment)
+}
+
 // CreateNewToken creates an instance of Token override. Call ``.Do(ctx)`` on the instance to actually create new token.
 // Default value for instance follows vault token documentation,
 // on here: https://www.vaultproject.io/api/auth/token#parameters
 //
 // Which means the new token will be renewable by default and has display name of 'token'
+//
+// If Init has not been called, ``.Do(ctx)`` returns an error instead.
 func CreateNewToken() *CreateTokenInstance {
+	if err := checkNil(); err != nil {
+		return &CreateTokenInstance{
+			Renewable:   true,
+			DisplayName: "token",
+			err:         err,
+		}
+	}
 	return gVault.CreateNewToken()
 }
 
@@ -165,6 +216,96 @@ func TransitDecryptStream(ctx context.Context, key string, cipher io.Reader) (pa
 	return gVault.TransitDecryptStream(ctx, key, cipher)
 }
 
+// TransitGenerateDataKey generates a new high entropy data key wrapped by transit key 'key'.
+// 'bits' may be 128, 256 or 512. Zero uses Vault's default of 256.
+func TransitGenerateDataKey(ctx context.Context, key string, keyType DataKeyType, bits int) (dk DataKey, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.TransitGenerateDataKey(ctx, key, keyType, bits)
+}
+
+// TransitEnvelopeEncrypt encrypts payload locally with a data key generated from transit key 'key'.
+// Memory usage is constant regardless of payload size. Use this for files too big for TransitEncryptStream.
+func TransitEnvelopeEncrypt(ctx context.Context, key string, payload io.Reader) (cipher io.Reader, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.TransitEnvelopeEncrypt(ctx, key, payload)
+}
+
+// TransitEnvelopeDecrypt decrypts a stream produced by TransitEnvelopeEncrypt chunk by chunk.
+func TransitEnvelopeDecrypt(ctx context.Context, key string, cipher io.Reader) (payload io.Reader, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.TransitEnvelopeDecrypt(ctx, key, cipher)
+}
+
+// GenerateRandom returns 'n' random bytes from Vault's sys/tools/random endpoint, encoded in given format.
+func GenerateRandom(ctx context.Context, n int, format OutputFormat, source RandomSource) (random string, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.GenerateRandom(ctx, n, format, source)
+}
+
+// TransitRandom returns 'n' random bytes from the transit engine, encoded in given format.
+func TransitRandom(ctx context.Context, n int, format OutputFormat, source RandomSource) (random string, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.TransitRandom(ctx, n, format, source)
+}
+
+// TransitHash hashes payload using the transit engine and returns the sum encoded in given format.
+func TransitHash(ctx context.Context, payload []byte, algorithm HashAlgorithm, format OutputFormat) (sum string, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.TransitHash(ctx, payload, algorithm, format)
+}
+
+// TransitExport exports an exportable transit key. Empty version exports all versions.
+func TransitExport(ctx context.Context, keyType ExportKeyType, key, version string) (exported ExportedKey, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.TransitExport(ctx, keyType, key, version)
+}
+
+// TransitBackup returns a plaintext backup of transit key 'key'.
+func TransitBackup(ctx context.Context, key string) (backup string, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.TransitBackup(ctx, key)
+}
+
+// TransitRestore restores a backup made by TransitBackup as transit key 'key'.
+func TransitRestore(ctx context.Context, key, backup string, force bool) (err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.TransitRestore(ctx, key, backup, force)
+}
+
+// TransitReadKey returns the configuration of transit key 'key'
+func TransitReadKey(ctx context.Context, key string) (cfg TransitKeyConfig, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.TransitReadKey(ctx, key)
+}
+
+// TransitCheckCiphertext parses the ciphertext and checks it against the current config of transit key 'key'
+func TransitCheckCiphertext(ctx context.Context, key, cipherText string) (err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.TransitCheckCiphertext(ctx, key, cipherText)
+}
+
 // CheckPolicy checks if policy exists and gets it's data
 func CheckPolicy(ctx context.Context, policy string) (d PolicyData, err error) {
 	if err = checkNil(); err != nil {
@@ -183,6 +324,22 @@ func UpsertPolicy(ctx context.Context, policy string, permissions map[string][]C
 	return gVault.UpsertPolicy(ctx, policy, permissions)
 }
 
+// ReadPolicy reads policy 'name' and parses it into a structured Policy
+func ReadPolicy(ctx context.Context, name string) (p Policy, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.ReadPolicy(ctx, name)
+}
+
+// UpsertPolicyModel creates/updates the policy rendered as HCL, named after Policy.Name
+func UpsertPolicyModel(ctx context.Context, p Policy) (err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.UpsertPolicyModel(ctx, p)
+}
+
 // RevokeToken revokes given token string.
 // Cannot revoke root token if instance token is not root
 // If getting permission denied error, then it's most likely that reason.
@@ -193,6 +350,80 @@ func RevokeToken(ctx context.Context, token string) (err error) {
 	return gVault.RevokeToken(ctx, token)
 }
 
+// ListAccessors lists accessors of all tokens
+func ListAccessors(ctx context.Context) (accessors []string, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.ListAccessors(ctx)
+}
+
+// LookupAccessor lookup information on the token of given accessor
+func LookupAccessor(ctx context.Context, accessor string) (lookup LookupToken, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.LookupAccessor(ctx, accessor)
+}
+
+// LookupAllAccessors walks every accessor and returns the lookup result of each token
+func LookupAllAccessors(ctx context.Context) (lookups []LookupToken, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.LookupAllAccessors(ctx)
+}
+
+// RenewTokenAccessor attempts to renew the token of given accessor using self's token
+func RenewTokenAccessor(ctx context.Context, accessor string) (err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.RenewTokenAccessor(ctx, accessor)
+}
+
+// RevokeAccessor revokes the token of given accessor and all of its children
+func RevokeAccessor(ctx context.Context, accessor string) (err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.RevokeAccessor(ctx, accessor)
+}
+
+// RevokeSelf revokes the token used in the instance and all of its children.
+// Global functions cannot be used afterwards until Init is called with a new token.
+func RevokeSelf(ctx context.Context) (err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.RevokeSelf(ctx)
+}
+
+// RevokeOrphan revokes given token string but keeps its children, which become orphans.
+func RevokeOrphan(ctx context.Context, token string) (err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.RevokeOrphan(ctx, token)
+}
+
+// TidyTokens starts cleaning up left over token entries and leases in the background.
+func TidyTokens(ctx context.Context) (err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.TidyTokens(ctx)
+}
+
+// ClientWithToken returns a lightweight copy of the global instance authenticating as 'token'.
+// The copy shares the same http client and configuration.
+func ClientWithToken(token string) (v *Vault, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.WithToken(token), nil
+}
+
 // GetConfigInstance returns the config used by this instance
 func GetConfigInstance() Config {
 	return gVault.Config
@@ -223,3 +454,535 @@ func UpsertKeyValue(ctx context.Context, key string, data interface{}) (err erro
 	}
 	return gVault.UpsertKeyValue(ctx, key, data)
 }
+
+// ListPolicies returns names of every ACL policy, including the built-in 'root' and 'default'
+func ListPolicies(ctx context.Context) (names []string, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.ListPolicies(ctx)
+}
+
+// DeletePolicy deletes policy 'name'. Deleting a policy which does not exist is not an error.
+func DeletePolicy(ctx context.Context, name string) (err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.DeletePolicy(ctx, name)
+}
+
+// SyncPolicies makes the ACL policies in Vault match 'desired', keyed by policy name. See Vault.SyncPolicies.
+func SyncPolicies(ctx context.Context, desired map[string]Policy, opts SyncOptions) (plan PolicyPlan, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.SyncPolicies(ctx, desired, opts)
+}
+
+// CapabilitiesSelf returns the capabilities the global instance token has on each of 'paths'
+func CapabilitiesSelf(ctx context.Context, paths ...string) (CapabilityMap, error) {
+	if err := checkNil(); err != nil {
+		return nil, err
+	}
+	return gVault.CapabilitiesSelf(ctx, paths...)
+}
+
+// Capabilities returns the capabilities 'token' has on each of 'paths'
+func Capabilities(ctx context.Context, token string, paths ...string) (CapabilityMap, error) {
+	if err := checkNil(); err != nil {
+		return nil, err
+	}
+	return gVault.Capabilities(ctx, token, paths...)
+}
+
+// CapabilitiesAccessor returns the capabilities the token of 'accessor' has on each of 'paths'
+func CapabilitiesAccessor(ctx context.Context, accessor string, paths ...string) (CapabilityMap, error) {
+	if err := checkNil(); err != nil {
+		return nil, err
+	}
+	return gVault.CapabilitiesAccessor(ctx, accessor, paths...)
+}
+
+// UpsertTokenRole creates/updates token role 'role.Name'
+func UpsertTokenRole(ctx context.Context, role TokenRole) (err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.UpsertTokenRole(ctx, role)
+}
+
+// ListTokenRoles returns names of every token role
+func ListTokenRoles(ctx context.Context) (names []string, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.ListTokenRoles(ctx)
+}
+
+// DeleteTokenRole deletes token role 'name'
+func DeleteTokenRole(ctx context.Context, name string) (err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.DeleteTokenRole(ctx, name)
+}
+
+// UpsertPolicyTemplate renders the template with 'data', and creates/updates the resulting policy
+func UpsertPolicyTemplate(ctx context.Context, t *PolicyTemplate, data interface{}) (p Policy, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.UpsertPolicyTemplate(ctx, t, data)
+}
+
+// ApplyPolicyTemplateToken renders and upserts the policy, then creates a token with it using 'token'
+func ApplyPolicyTemplateToken(ctx context.Context, t *PolicyTemplate, data interface{}, token *CreateTokenInstance) (p Policy, clientToken string, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.ApplyPolicyTemplateToken(ctx, t, data, token)
+}
+
+// ApplyPolicyTemplateRole renders and upserts the policy, then creates/updates token role 'role' allowing it
+func ApplyPolicyTemplateRole(ctx context.Context, t *PolicyTemplate, data interface{}, role TokenRole) (p Policy, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.ApplyPolicyTemplateRole(ctx, t, data, role)
+}
+
+// Health returns the health of the Vault node. err is nil only when the node is healthy, see Vault.Health.
+func Health(ctx context.Context) (h HealthStatus, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.Health(ctx)
+}
+
+// SealStatus returns the seal status of the Vault node
+func SealStatus(ctx context.Context) (s SealState, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.SealStatus(ctx)
+}
+
+// Leader returns the high availability status and the address of the active node
+func Leader(ctx context.Context) (l LeaderStatus, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.Leader(ctx)
+}
+
+// HealthHandler returns an http.Handler serving the combined Vault status of the global instance, for readiness probes.
+// It responds with 503 until Init has been called.
+func HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := checkNil(); err != nil {
+			writeDependencyStatus(w, DependencyStatus{Error: err.Error()})
+			return
+		}
+		gVault.HealthHandler().ServeHTTP(w, r)
+	})
+}
+
+// ListMounts returns every enabled secrets engine, keyed by path without trailing '/'
+func ListMounts(ctx context.Context) (mounts map[string]Mount, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.ListMounts(ctx)
+}
+
+// EnableMount enables a secrets engine of type opts.Type at 'path'
+func EnableMount(ctx context.Context, path string, opts MountOptions) (err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.EnableMount(ctx, path, opts)
+}
+
+// TuneMount updates the configuration of the mount at 'path'. Zero values of opts are left unchanged.
+func TuneMount(ctx context.Context, path string, opts MountOptions) (err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.TuneMount(ctx, path, opts)
+}
+
+// ReadMountConfig returns the tunable configuration of the mount at 'path'
+func ReadMountConfig(ctx context.Context, path string) (c MountConfig, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.ReadMountConfig(ctx, path)
+}
+
+// DisableMount disables the mount at 'path', deleting every secret stored in it
+func DisableMount(ctx context.Context, path string) (err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.DisableMount(ctx, path)
+}
+
+// EnsureMount makes sure a secrets engine of type opts.Type is mounted at 'path' with the given options
+func EnsureMount(ctx context.Context, path string, opts MountOptions) (created bool, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.EnsureMount(ctx, path, opts)
+}
+
+// ListAuthMethods returns every enabled auth method, keyed by path without trailing '/'
+func ListAuthMethods(ctx context.Context) (methods map[string]Mount, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.ListAuthMethods(ctx)
+}
+
+// EnableAuthMethod enables an auth method of type opts.Type at 'path'
+func EnableAuthMethod(ctx context.Context, path string, opts MountOptions) (err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.EnableAuthMethod(ctx, path, opts)
+}
+
+// TuneAuthMethod updates the configuration of the auth method at 'path'. Zero values of opts are left unchanged.
+func TuneAuthMethod(ctx context.Context, path string, opts MountOptions) (err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.TuneAuthMethod(ctx, path, opts)
+}
+
+// ReadAuthMethodConfig returns the tunable configuration of the auth method at 'path'
+func ReadAuthMethodConfig(ctx context.Context, path string) (c MountConfig, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.ReadAuthMethodConfig(ctx, path)
+}
+
+// DisableAuthMethod disables the auth method at 'path', revoking every token issued through it
+func DisableAuthMethod(ctx context.Context, path string) (err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.DisableAuthMethod(ctx, path)
+}
+
+// EnsureAuthMethod makes sure an auth method of type opts.Type is enabled at 'path' with the given options
+func EnsureAuthMethod(ctx context.Context, path string, opts MountOptions) (created bool, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.EnsureAuthMethod(ctx, path, opts)
+}
+
+// UpsertAppRole creates/updates AppRole 'role.Name'
+func UpsertAppRole(ctx context.Context, role AppRole) (err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.UpsertAppRole(ctx, role)
+}
+
+// ListAppRoles returns names of every AppRole
+func ListAppRoles(ctx context.Context) (names []string, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.ListAppRoles(ctx)
+}
+
+// DeleteAppRole deletes AppRole 'name'
+func DeleteAppRole(ctx context.Context, name string) (err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.DeleteAppRole(ctx, name)
+}
+
+// AppRoleID returns the role ID of AppRole 'name'
+func AppRoleID(ctx context.Context, name string) (roleID string, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.AppRoleID(ctx, name)
+}
+
+// GenerateAppRoleSecretID generates a new secret ID for AppRole 'name'
+func GenerateAppRoleSecretID(ctx context.Context, name string, metadata map[string]string) (s AppRoleSecretID, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.GenerateAppRoleSecretID(ctx, name, metadata)
+}
+
+// ConfigureKubernetesAuth writes the configuration of the Kubernetes auth method
+func ConfigureKubernetesAuth(ctx context.Context, c KubernetesAuthConfig) (err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.ConfigureKubernetesAuth(ctx, c)
+}
+
+// UpsertKubernetesRole creates/updates Kubernetes auth role 'role.Name'
+func UpsertKubernetesRole(ctx context.Context, role KubernetesRole) (err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.UpsertKubernetesRole(ctx, role)
+}
+
+// ListKubernetesRoles returns names of every Kubernetes auth role
+func ListKubernetesRoles(ctx context.Context) (names []string, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.ListKubernetesRoles(ctx)
+}
+
+// DeleteKubernetesRole deletes Kubernetes auth role 'name'
+func DeleteKubernetesRole(ctx context.Context, name string) (err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.DeleteKubernetesRole(ctx, name)
+}
+
+// LookupLease returns the state of lease 'leaseID'
+func LookupLease(ctx context.Context, leaseID string) (l Lease, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.LookupLease(ctx, leaseID)
+}
+
+// RenewLease extends lease 'leaseID' by 'increment' from now. Zero increment uses the engine's default TTL.
+func RenewLease(ctx context.Context, leaseID string, increment time.Duration) (l LeaseInfo, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.RenewLease(ctx, leaseID, increment)
+}
+
+// RevokeLease revokes lease 'leaseID' immediately
+func RevokeLease(ctx context.Context, leaseID string) (err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.RevokeLease(ctx, leaseID)
+}
+
+// RevokePrefix revokes every lease whose ID starts with 'prefix'
+func RevokePrefix(ctx context.Context, prefix string) (err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.RevokePrefix(ctx, prefix)
+}
+
+// ListLeases returns the keys under lease ID 'prefix'
+func ListLeases(ctx context.Context, prefix string) (keys []string, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.ListLeases(ctx, prefix)
+}
+
+// ReadSecret reads the secret at 'path', like "database/creds/ms-order", including its lease
+func ReadSecret(ctx context.Context, path string) (c ConfigResponse, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.ReadSecret(ctx, path)
+}
+
+// NewLeaseWatcher creates a watcher of the lease carried by 'holder', renewing it with the global instance
+func NewLeaseWatcher(holder LeaseHolder, opts LeaseWatcherOptions) (w *LeaseWatcher, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.NewLeaseWatcher(holder, opts)
+}
+
+// GetDatabaseCredentials issues new credentials of role 'role'
+func GetDatabaseCredentials(ctx context.Context, role string) (c DatabaseCredentials, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.GetDatabaseCredentials(ctx, role)
+}
+
+// GetDatabaseStaticCredentials returns the current credentials of static role 'role'
+func GetDatabaseStaticCredentials(ctx context.Context, role string) (c DatabaseStaticCredentials, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.GetDatabaseStaticCredentials(ctx, role)
+}
+
+// UpsertDatabaseConnection creates/updates connection 'c.Name'
+func UpsertDatabaseConnection(ctx context.Context, c DatabaseConnection) (err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.UpsertDatabaseConnection(ctx, c)
+}
+
+// ListDatabaseConnections returns names of every connection
+func ListDatabaseConnections(ctx context.Context) (names []string, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.ListDatabaseConnections(ctx)
+}
+
+// DeleteDatabaseConnection deletes connection 'name'
+func DeleteDatabaseConnection(ctx context.Context, name string) (err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.DeleteDatabaseConnection(ctx, name)
+}
+
+// RotateDatabaseRoot rotates the password of the root user of connection 'name'
+func RotateDatabaseRoot(ctx context.Context, name string) (err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.RotateDatabaseRoot(ctx, name)
+}
+
+// UpsertDatabaseRole creates/updates dynamic role 'r.Name'
+func UpsertDatabaseRole(ctx context.Context, r DatabaseRole) (err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.UpsertDatabaseRole(ctx, r)
+}
+
+// ListDatabaseRoles returns names of every dynamic role
+func ListDatabaseRoles(ctx context.Context) (names []string, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.ListDatabaseRoles(ctx)
+}
+
+// DeleteDatabaseRole deletes dynamic role 'name'
+func DeleteDatabaseRole(ctx context.Context, name string) (err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.DeleteDatabaseRole(ctx, name)
+}
+
+// UpsertDatabaseStaticRole creates/updates static role 'r.Name'
+func UpsertDatabaseStaticRole(ctx context.Context, r DatabaseStaticRole) (err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.UpsertDatabaseStaticRole(ctx, r)
+}
+
+// DeleteDatabaseStaticRole deletes static role 'name'
+func DeleteDatabaseStaticRole(ctx context.Context, name string) (err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.DeleteDatabaseStaticRole(ctx, name)
+}
+
+// RotateDatabaseStaticRole rotates the password of static role 'name' now
+func RotateDatabaseStaticRole(ctx context.Context, name string) (err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.RotateDatabaseStaticRole(ctx, name)
+}
+
+// WatchDatabaseDSN issues credentials of role 'role', builds a DSN with 'build', and keeps it valid until ctx is cancelled
+func WatchDatabaseDSN(ctx context.Context, role string, build DSNBuilder, opts DatabaseDSNOptions) (d *DatabaseDSN, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.WatchDatabaseDSN(ctx, role, build, opts)
+}
+
+// IssueCertificate issues a new certificate and private key from role 'role'
+func IssueCertificate(ctx context.Context, role string, req CertificateRequest) (c IssuedCertificate, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.IssueCertificate(ctx, role, req)
+}
+
+// SignCSR signs the PEM encoded certificate signing request 'csr' with role 'role'
+func SignCSR(ctx context.Context, role string, csr []byte, req CertificateRequest) (c IssuedCertificate, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.SignCSR(ctx, role, csr, req)
+}
+
+// RevokeCertificate revokes the certificate with serial number 'serial'. Returns the revocation time.
+func RevokeCertificate(ctx context.Context, serial string) (revokedAt time.Time, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.RevokeCertificate(ctx, serial)
+}
+
+// ReadCertificate returns the certificate with serial number 'serial'
+func ReadCertificate(ctx context.Context, serial string) (cert *x509.Certificate, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.ReadCertificate(ctx, serial)
+}
+
+// ReadCAChain returns the CA chain of the PKI engine, issuing CA first
+func ReadCAChain(ctx context.Context) (chain []*x509.Certificate, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.ReadCAChain(ctx)
+}
+
+// CAPool returns a pool of the PKI engine's CA chain
+func CAPool(ctx context.Context) (pool *x509.CertPool, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.CAPool(ctx)
+}
+
+// ReadCRL returns the current certificate revocation list of the PKI engine
+func ReadCRL(ctx context.Context) (crl *pkix.CertificateList, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.ReadCRL(ctx)
+}
+
+// ListCertificates returns serial numbers of every certificate issued by the PKI engine, colon separated like RevokeCertificate expects
+func ListCertificates(ctx context.Context) (serials []string, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.ListCertificates(ctx)
+}
+
+// TLSConfig issues a certificate from role 'role' and returns a tls.Config re-issuing it before it expires
+func TLSConfig(ctx context.Context, role string, req CertificateRequest, opts TLSOptions) (conf *tls.Config, err error) {
+	if err = checkNil(); err != nil {
+		return
+	}
+	return gVault.TLSConfig(ctx, role, req, opts)
+}
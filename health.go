@@ -0,0 +1,193 @@
+package forest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+var (
+	// ErrNotInitialized is returned by Health when Vault has not been initialized
+	ErrNotInitialized = errors.New("vault is not initialized")
+	// ErrSealed is returned by Health when Vault is sealed
+	ErrSealed = errors.New("vault is sealed")
+	// ErrStandby is returned by Health when the node is a standby, unless WithHealthStandbyOK is set
+	ErrStandby = errors.New("vault node is standby")
+	// ErrPerfStandby is returned by Health when the node is a performance standby, unless WithHealthPerfStandbyOK is set
+	ErrPerfStandby = errors.New("vault node is performance standby")
+	// ErrDRSecondary is returned by Health when the node is a disaster recovery secondary
+	ErrDRSecondary = errors.New("vault node is disaster recovery secondary")
+)
+
+// HealthStatus is the response of sys/health
+type HealthStatus struct {
+	Initialized                bool   `json:"initialized"`
+	Sealed                     bool   `json:"sealed"`
+	Standby                    bool   `json:"standby"`
+	PerformanceStandby         bool   `json:"performance_standby"`
+	ReplicationPerformanceMode string `json:"replication_performance_mode"`
+	ReplicationDRMode          string `json:"replication_dr_mode"`
+	ServerTimeUTC              int64  `json:"server_time_utc"`
+	Version                    string `json:"version"`
+	ClusterName                string `json:"cluster_name"`
+	ClusterID                  string `json:"cluster_id"`
+	StatusCode                 int    `json:"-"` // HTTP status code returned by Vault
+}
+
+// ServerTime returns the server time reported by Vault
+func (h HealthStatus) ServerTime() time.Time {
+	return time.Unix(h.ServerTimeUTC, 0)
+}
+
+// Err returns the reason the node is not healthy, or nil if it is
+func (h HealthStatus) Err() error {
+	switch h.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusTooManyRequests:
+		return ErrStandby
+	case 472:
+		return ErrDRSecondary
+	case 473:
+		return ErrPerfStandby
+	case http.StatusNotImplemented:
+		return ErrNotInitialized
+	case http.StatusServiceUnavailable:
+		return ErrSealed
+	}
+	return fmt.Errorf("unexpected health status code %d", h.StatusCode)
+}
+
+// Health returns the health of the Vault node. err is nil only when the node is initialized, unsealed and active,
+// or standby / performance standby if WithHealthStandbyOK / WithHealthPerfStandbyOK is set.
+// The status is returned whenever Vault responded, even if it's not healthy.
+//
+// Example: `if _, err := vault.Health(ctx); errors.Is(err, forest.ErrSealed) { ... }`
+func (v *Vault) Health(ctx context.Context) (h HealthStatus, err error) {
+	query := url.Values{}
+	if v.Config.HealthStandbyOK {
+		query.Set("standbyok", "true")
+	}
+	if v.Config.HealthPerfStandbyOK {
+		query.Set("perfstandbyok", "true")
+	}
+	path := "/sys/health"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+	req, err := v.requestGen(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return
+	}
+	// Unhealthy nodes respond with non 2xx codes and a regular body, so checkErrorResponse does not apply here
+	res, err := v.Config.HTTPClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+	if err = json.NewDecoder(res.Body).Decode(&h); err != nil {
+		return h, fmt.Errorf("Fail to decode health response with status code %d: %w", res.StatusCode, err)
+	}
+	h.StatusCode = res.StatusCode
+	return h, h.Err()
+}
+
+// SealState is the response of sys/seal-status
+type SealState struct {
+	Type         string `json:"type"`
+	Initialized  bool   `json:"initialized"`
+	Sealed       bool   `json:"sealed"`
+	Threshold    int    `json:"t"`
+	Shares       int    `json:"n"`
+	Progress     int    `json:"progress"`
+	Nonce        string `json:"nonce"`
+	Version      string `json:"version"`
+	BuildDate    string `json:"build_date"`
+	Migration    bool   `json:"migration"`
+	ClusterName  string `json:"cluster_name"`
+	ClusterID    string `json:"cluster_id"`
+	RecoverySeal bool   `json:"recovery_seal"`
+	StorageType  string `json:"storage_type"`
+}
+
+// SealStatus returns the seal status of the Vault node, including unseal progress
+func (v *Vault) SealStatus(ctx context.Context) (s SealState, err error) {
+	err = v.doJSON(ctx, http.MethodGet, "/sys/seal-status", nil, &s)
+	return
+}
+
+// LeaderStatus is the response of sys/leader
+type LeaderStatus struct {
+	HAEnabled            bool      `json:"ha_enabled"`
+	IsSelf               bool      `json:"is_self"`
+	ActiveTime           time.Time `json:"active_time"`
+	LeaderAddress        string    `json:"leader_address"`
+	LeaderClusterAddress string    `json:"leader_cluster_address"`
+	PerformanceStandby   bool      `json:"performance_standby"`
+	RaftCommittedIndex   uint64    `json:"raft_committed_index"`
+	RaftAppliedIndex     uint64    `json:"raft_applied_index"`
+}
+
+// Leader returns the high availability status and the address of the active node
+func (v *Vault) Leader(ctx context.Context) (l LeaderStatus, err error) {
+	err = v.doJSON(ctx, http.MethodGet, "/sys/leader", nil, &l)
+	return
+}
+
+// DependencyStatus is the combined Vault status served by HealthHandler
+type DependencyStatus struct {
+	Healthy bool          `json:"healthy"`
+	Error   string        `json:"error,omitempty"`
+	Health  *HealthStatus `json:"health,omitempty"`
+	Seal    *SealState    `json:"seal,omitempty"`
+	Leader  *LeaderStatus `json:"leader,omitempty"`
+}
+
+// DependencyStatus checks health, seal status and leader of Vault. Healthy follows the result of Health.
+func (v *Vault) DependencyStatus(ctx context.Context) (d DependencyStatus) {
+	h, err := v.Health(ctx)
+	if h.StatusCode != 0 {
+		d.Health = &h
+	}
+	if err != nil {
+		d.Error = err.Error()
+		if d.Health == nil {
+			// Vault is unreachable, no point in asking further
+			return
+		}
+	}
+	d.Healthy = err == nil
+	if s, err := v.SealStatus(ctx); err == nil {
+		d.Seal = &s
+	}
+	if l, err := v.Leader(ctx); err == nil {
+		d.Leader = &l
+	}
+	return
+}
+
+// HealthHandler returns an http.Handler serving DependencyStatus as JSON, for readiness probes.
+// It responds with 200 when Vault is healthy and 503 otherwise. Each check is bound to the request context,
+// so set a timeout on the probe.
+//
+// Example: `http.Handle("/ready/vault", vault.HealthHandler())`
+func (v *Vault) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeDependencyStatus(w, v.DependencyStatus(r.Context()))
+	})
+}
+
+func writeDependencyStatus(w http.ResponseWriter, d DependencyStatus) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if d.Healthy {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(d)
+}
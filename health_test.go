@@ -0,0 +1,86 @@
+package forest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Health(t *testing.T) {
+	h, err := Health(context.TODO())
+	require.NoError(t, err)
+	assert.True(t, h.Initialized)
+	assert.False(t, h.Sealed)
+	assert.Equal(t, http.StatusOK, h.StatusCode)
+
+	s, err := SealStatus(context.TODO())
+	require.NoError(t, err)
+	assert.False(t, s.Sealed)
+	assert.Equal(t, h.Version, s.Version)
+
+	_, err = Leader(context.TODO())
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	HealthHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var d DependencyStatus
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&d))
+	assert.True(t, d.Healthy)
+	assert.NotNil(t, d.Seal)
+	assert.NotNil(t, d.Leader)
+}
+
+func Test_Health_Unhealthy(t *testing.T) {
+	var code int
+	var query string
+	v, server := fakeVault(t, map[string]http.HandlerFunc{
+		"/sys/health": func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.RawQuery
+			w.WriteHeader(code)
+			json.NewEncoder(w).Encode(map[string]interface{}{"initialized": true, "sealed": code == http.StatusServiceUnavailable, "standby": code == 429})
+		},
+	})
+	defer server.Close()
+
+	tests := []struct {
+		code int
+		want error
+	}{
+		{http.StatusServiceUnavailable, ErrSealed},
+		{http.StatusNotImplemented, ErrNotInitialized},
+		{http.StatusTooManyRequests, ErrStandby},
+		{473, ErrPerfStandby},
+		{472, ErrDRSecondary},
+	}
+	for _, tt := range tests {
+		code = tt.code
+		h, err := v.Health(context.TODO())
+		assert.True(t, errors.Is(err, tt.want), "%d: %v", tt.code, err)
+		assert.Equal(t, tt.code, h.StatusCode)
+	}
+	assert.Empty(t, query)
+
+	code = http.StatusServiceUnavailable
+	rec := httptest.NewRecorder()
+	v.HealthHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	var d DependencyStatus
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&d))
+	assert.False(t, d.Healthy)
+	assert.Equal(t, ErrSealed.Error(), d.Error)
+	assert.True(t, d.Health.Sealed)
+
+	v, err := NewClient("x", WithHost(server.URL), WithHealthStandbyOK(), WithHealthPerfStandbyOK())
+	require.NoError(t, err)
+	code = http.StatusOK
+	_, err = v.Health(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, "perfstandbyok=true&standbyok=true", query)
+}
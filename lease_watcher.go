@@ -0,0 +1,216 @@
+package forest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LeaseHolder is implemented by anything carrying a lease, like ConfigResponse returned by ReadSecret.
+// LeaseInfo implements it too, so a lease from any response can be watched.
+type LeaseHolder interface {
+	LeaseInfo() LeaseInfo
+}
+
+// LeaseInfo returns itself, so LeaseInfo can be passed as a LeaseHolder
+func (l LeaseInfo) LeaseInfo() LeaseInfo {
+	return l
+}
+
+// LeaseInfo returns the lease of the secret
+func (c ConfigResponse) LeaseInfo() LeaseInfo {
+	return LeaseInfo{LeaseID: c.LeaseID, Renewable: c.Renewable, LeaseDuration: int64(c.LeaseDuration)}
+}
+
+// ReadSecret reads the secret at 'path', like "database/creds/ms-order", including its lease.
+// Use it for dynamic secrets whose lease has to be kept alive with a LeaseWatcher.
+func (v *Vault) ReadSecret(ctx context.Context, path string) (c ConfigResponse, err error) {
+	path = strings.TrimPrefix(path, "/")
+	if path == "" {
+		return c, errors.New("path cannot be empty")
+	}
+	err = v.doJSON(ctx, http.MethodGet, "/"+path, nil, &c)
+	return
+}
+
+// LeaseEventType is the kind of LeaseEvent
+type LeaseEventType string
+
+const (
+	// LeaseRenewed is sent after every successful renewal
+	LeaseRenewed LeaseEventType = "renewed"
+	// LeaseExpiring is sent once when the lease can no longer be extended, because it's not renewable,
+	// it has reached its max TTL or renewals kept failing. The secret should be replaced before it expires.
+	LeaseExpiring LeaseEventType = "expiring"
+	// LeaseExpired is sent once the lease has expired. It's always the last event.
+	LeaseExpired LeaseEventType = "expired"
+	// LeaseError is sent when a renewal fails. The watcher keeps retrying while the lease has time left for it.
+	LeaseError LeaseEventType = "error"
+)
+
+// LeaseEvent is sent by LeaseWatcher
+type LeaseEvent struct {
+	Type      LeaseEventType
+	Lease     LeaseInfo // Latest known state of the lease
+	ExpiresAt time.Time
+	Err       error // Set on LeaseError only
+}
+
+// LeaseWatcherOptions configures a LeaseWatcher
+type LeaseWatcherOptions struct {
+	// RenewFraction of the TTL after which the lease is renewed. Defaults to 2/3.
+	RenewFraction float64
+	// Increment requested on each renewal. Defaults to the initial lease duration.
+	Increment time.Duration
+	// RetryInterval between failed renewals. Defaults to 5 seconds, and never goes past the expiration.
+	RetryInterval time.Duration
+}
+
+// LeaseWatcher keeps a lease alive by renewing it at a fraction of its TTL, and reports what happens on Events.
+// It stops after the lease has expired or when the context passed to Start is cancelled,
+// closing the events channel in both cases.
+//
+// Example:
+//
+//	secret, err := vault.ReadSecret(ctx, "database/creds/ms-order")
+//	w, err := vault.NewLeaseWatcher(secret, forest.LeaseWatcherOptions{})
+//	w.Start(ctx)
+//	for e := range w.Events() {
+//		if e.Type == forest.LeaseExpiring {
+//			// read a new secret
+//		}
+//	}
+type LeaseWatcher struct {
+	vault     *Vault
+	opts      LeaseWatcherOptions
+	events    chan LeaseEvent
+	start     sync.Once
+	mu        sync.Mutex
+	lease     LeaseInfo
+	expiresAt time.Time
+}
+
+// NewLeaseWatcher creates a watcher of the lease carried by 'holder'. Call Start to begin renewing.
+func (v *Vault) NewLeaseWatcher(holder LeaseHolder, opts LeaseWatcherOptions) (*LeaseWatcher, error) {
+	lease := holder.LeaseInfo()
+	if lease.LeaseID == "" {
+		return nil, errors.New("secret has no lease")
+	}
+	if lease.LeaseDuration <= 0 {
+		return nil, fmt.Errorf("lease '%s' has no duration", lease.LeaseID)
+	}
+	if opts.RenewFraction == 0 {
+		opts.RenewFraction = 2.0 / 3
+	}
+	if opts.RenewFraction <= 0 || opts.RenewFraction >= 1 {
+		return nil, errors.New("renew fraction must be between 0 and 1")
+	}
+	if opts.Increment == 0 {
+		opts.Increment = lease.TTL()
+	}
+	if opts.RetryInterval == 0 {
+		opts.RetryInterval = 5 * time.Second
+	}
+	return &LeaseWatcher{
+		vault:     v,
+		opts:      opts,
+		events:    make(chan LeaseEvent, 8),
+		lease:     lease,
+		expiresAt: time.Now().Add(lease.TTL()),
+	}, nil
+}
+
+// Events returns the channel events are sent to. It must be drained, since the watcher waits for events to be received.
+func (w *LeaseWatcher) Events() <-chan LeaseEvent {
+	return w.events
+}
+
+// Lease returns the latest known state of the lease, and when it expires
+func (w *LeaseWatcher) Lease() (LeaseInfo, time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.lease, w.expiresAt
+}
+
+// Start begins renewing in the background until the lease expires or ctx is cancelled. Calling it again has no effect.
+func (w *LeaseWatcher) Start(ctx context.Context) {
+	w.start.Do(func() {
+		go w.run(ctx)
+	})
+}
+
+func (w *LeaseWatcher) run(ctx context.Context) {
+	defer close(w.events)
+	lease, expiresAt := w.Lease()
+	wait := time.Duration(float64(lease.TTL()) * w.opts.RenewFraction)
+	for lease.Renewable {
+		if !sleepContext(ctx, wait) {
+			return
+		}
+		renewed, err := w.vault.RenewLease(ctx, lease.LeaseID, w.opts.Increment)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if !w.send(ctx, LeaseEvent{Type: LeaseError, Lease: lease, ExpiresAt: expiresAt, Err: err}) {
+				return
+			}
+			// Giving up, the next retry would come after the lease has expired
+			if wait = w.opts.RetryInterval; wait >= time.Until(expiresAt) {
+				break
+			}
+			continue
+		}
+		lease, expiresAt = renewed, time.Now().Add(renewed.TTL())
+		w.mu.Lock()
+		w.lease, w.expiresAt = lease, expiresAt
+		w.mu.Unlock()
+		if !w.send(ctx, LeaseEvent{Type: LeaseRenewed, Lease: lease, ExpiresAt: expiresAt}) {
+			return
+		}
+		// Vault grants less than asked once the max TTL is reached, further renewals won't extend the lease
+		if lease.LeaseDuration <= 0 || lease.TTL() < w.opts.Increment {
+			break
+		}
+		wait = time.Duration(float64(lease.TTL()) * w.opts.RenewFraction)
+	}
+	if !w.send(ctx, LeaseEvent{Type: LeaseExpiring, Lease: lease, ExpiresAt: expiresAt}) {
+		return
+	}
+	w.expire(ctx, lease, expiresAt)
+}
+
+// expire waits for the lease to expire and sends LeaseExpired
+func (w *LeaseWatcher) expire(ctx context.Context, lease LeaseInfo, expiresAt time.Time) {
+	if sleepContext(ctx, time.Until(expiresAt)) {
+		w.send(ctx, LeaseEvent{Type: LeaseExpired, Lease: lease, ExpiresAt: expiresAt})
+	}
+}
+
+func (w *LeaseWatcher) send(ctx context.Context, e LeaseEvent) bool {
+	select {
+	case w.events <- e:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// sleepContext waits for d, and returns false if ctx is done first
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
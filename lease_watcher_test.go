@@ -0,0 +1,118 @@
+package forest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// renewServer fakes sys/leases/renew, granting the given durations in turn. Negative durations fail the renewal.
+// Real leases that can be renewed need an engine backed by an external system, like a database.
+func renewServer(t *testing.T, durations ...int) (*Vault, *httptest.Server) {
+	var calls int32
+	return fakeVault(t, map[string]http.HandlerFunc{
+		"/sys/leases/renew": func(w http.ResponseWriter, r *http.Request) {
+			i := int(atomic.AddInt32(&calls, 1)) - 1
+			if i >= len(durations) || durations[i] < 0 {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"errors":["lease not found"]}`))
+				return
+			}
+			fmt.Fprintf(w, `{"lease_id":"database/creds/ms-order/abc","renewable":true,"lease_duration":%d}`, durations[i])
+		},
+	})
+}
+
+func collectEvents(w *LeaseWatcher) (types []LeaseEventType, events []LeaseEvent) {
+	for e := range w.Events() {
+		types = append(types, e.Type)
+		events = append(events, e)
+	}
+	return
+}
+
+var testLease = LeaseInfo{LeaseID: "database/creds/ms-order/abc", Renewable: true, LeaseDuration: 1}
+
+func Test_LeaseWatcher_MaxTTL(t *testing.T) {
+	// The second renewal is capped by the max TTL, it's still reported before the lease starts expiring
+	v, server := renewServer(t, 2, 1)
+	defer server.Close()
+	lease := testLease
+	lease.LeaseDuration = 2
+	w, err := v.NewLeaseWatcher(lease, LeaseWatcherOptions{RenewFraction: 0.1})
+	require.NoError(t, err)
+	w.Start(context.TODO())
+	types, events := collectEvents(w)
+	require.Equal(t, []LeaseEventType{LeaseRenewed, LeaseRenewed, LeaseExpiring, LeaseExpired}, types)
+	assert.Equal(t, int64(1), events[1].Lease.LeaseDuration)
+}
+
+func Test_LeaseWatcher_NotRenewable(t *testing.T) {
+	v, server := renewServer(t)
+	defer server.Close()
+	lease := testLease
+	lease.Renewable = false
+	w, err := v.NewLeaseWatcher(lease, LeaseWatcherOptions{RenewFraction: 0.5})
+	require.NoError(t, err)
+	w.Start(context.TODO())
+	// Nothing to wait for before a lease that can't be renewed starts expiring
+	start := time.Now()
+	e := <-w.Events()
+	assert.Equal(t, LeaseExpiring, e.Type)
+	assert.True(t, time.Since(start) < 100*time.Millisecond, "expiring after %s", time.Since(start))
+	types, _ := collectEvents(w)
+	assert.Equal(t, []LeaseEventType{LeaseExpired}, types)
+}
+
+func Test_LeaseWatcher_Error(t *testing.T) {
+	v, server := renewServer(t, -1, 1)
+	defer server.Close()
+	w, err := v.NewLeaseWatcher(testLease, LeaseWatcherOptions{RenewFraction: 0.1, RetryInterval: 100 * time.Millisecond})
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.TODO())
+	w.Start(ctx)
+
+	e := <-w.Events()
+	assert.Equal(t, LeaseError, e.Type)
+	assert.Error(t, e.Err)
+	e = <-w.Events()
+	assert.Equal(t, LeaseRenewed, e.Type)
+	lease, expiresAt := w.Lease()
+	assert.Equal(t, testLease, lease)
+	assert.WithinDuration(t, time.Now().Add(time.Second), expiresAt, 200*time.Millisecond)
+
+	// Channel is closed once cancelled, even if an event was pending
+	cancel()
+	for range w.Events() {
+	}
+}
+
+func Test_LeaseWatcher_RenewFails(t *testing.T) {
+	v, server := renewServer(t)
+	defer server.Close()
+	w, err := v.NewLeaseWatcher(testLease, LeaseWatcherOptions{RenewFraction: 0.1, RetryInterval: 300 * time.Millisecond})
+	require.NoError(t, err)
+	w.Start(context.TODO())
+	types, events := collectEvents(w)
+	require.True(t, len(types) >= 3, "events %v", types)
+	for _, e := range events[:len(events)-2] {
+		assert.Equal(t, LeaseError, e.Type)
+	}
+	assert.Equal(t, []LeaseEventType{LeaseExpiring, LeaseExpired}, types[len(types)-2:])
+}
+
+func Test_LeaseWatcher_Invalid(t *testing.T) {
+	_, err := NewLeaseWatcher(LeaseInfo{}, LeaseWatcherOptions{})
+	assert.Error(t, err)
+	_, err = NewLeaseWatcher(LeaseInfo{LeaseID: "x"}, LeaseWatcherOptions{})
+	assert.Error(t, err)
+	_, err = NewLeaseWatcher(testLease, LeaseWatcherOptions{RenewFraction: 1.5})
+	assert.Error(t, err)
+}
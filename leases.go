@@ -0,0 +1,108 @@
+package forest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Lease is the state of a lease, returned by LookupLease
+type Lease struct {
+	ID          string        `json:"id"`
+	IssueTime   time.Time     `json:"issue_time"`
+	ExpireTime  time.Time     `json:"expire_time"`
+	LastRenewal time.Time     `json:"last_renewal"` // Zero if never renewed
+	Renewable   bool          `json:"renewable"`
+	TTL         time.Duration `json:"-"`
+}
+
+// LeaseInfo is the lease of a leased secret, as found in responses of dynamic secrets and RenewLease
+type LeaseInfo struct {
+	LeaseID       string `json:"lease_id"`
+	Renewable     bool   `json:"renewable"`
+	LeaseDuration int64  `json:"lease_duration"` // Seconds. Use TTL()
+}
+
+// TTL returns the lease duration granted by Vault
+func (l LeaseInfo) TTL() time.Duration {
+	return time.Duration(l.LeaseDuration) * time.Second
+}
+
+type leaseRequest struct {
+	LeaseID   string `json:"lease_id"`
+	Increment string `json:"increment,omitempty"`
+}
+
+type leaseLookupResponse struct {
+	RequestID     string `json:"request_id"`
+	LeaseID       string `json:"lease_id"`
+	Renewable     bool   `json:"renewable"`
+	LeaseDuration int64  `json:"lease_duration"`
+	Data          struct {
+		Lease
+		TTL int64 `json:"ttl"`
+	} `json:"data"`
+}
+
+// LookupLease returns the state of lease 'leaseID'
+func (v *Vault) LookupLease(ctx context.Context, leaseID string) (l Lease, err error) {
+	if leaseID == "" {
+		return l, errors.New("lease id cannot be empty")
+	}
+	var response leaseLookupResponse
+	if err = v.doJSON(ctx, http.MethodPut, "/sys/leases/lookup", leaseRequest{LeaseID: leaseID}, &response); err != nil {
+		return
+	}
+	l = response.Data.Lease
+	l.TTL = time.Duration(response.Data.TTL) * time.Second
+	return
+}
+
+// RenewLease extends lease 'leaseID' by 'increment' from now. Zero increment uses the engine's default TTL.
+// Vault may grant less than asked, check the returned TTL. The lease can't be extended past its max TTL.
+//
+// Example: `info, err := vault.RenewLease(ctx, secret.LeaseID, time.Hour)`
+func (v *Vault) RenewLease(ctx context.Context, leaseID string, increment time.Duration) (l LeaseInfo, err error) {
+	if leaseID == "" {
+		return l, errors.New("lease id cannot be empty")
+	}
+	if increment < 0 {
+		return l, errors.New("increment cannot be negative")
+	}
+	body := leaseRequest{LeaseID: leaseID, Increment: optionalDuration(increment)}
+	err = v.doJSON(ctx, http.MethodPut, "/sys/leases/renew", body, &l)
+	return
+}
+
+// RevokeLease revokes lease 'leaseID' immediately, invalidating the secret it was issued with
+func (v *Vault) RevokeLease(ctx context.Context, leaseID string) (err error) {
+	if leaseID == "" {
+		return errors.New("lease id cannot be empty")
+	}
+	return v.doJSON(ctx, http.MethodPut, "/sys/leases/revoke", leaseRequest{LeaseID: leaseID}, nil)
+}
+
+// RevokePrefix revokes every lease whose ID starts with 'prefix', like "database/creds/ms-order/".
+// Instance token needs sudo on sys/leases/revoke-prefix.
+func (v *Vault) RevokePrefix(ctx context.Context, prefix string) (err error) {
+	prefix = strings.TrimPrefix(prefix, "/")
+	if prefix == "" {
+		return errors.New("prefix cannot be empty")
+	}
+	return v.doJSON(ctx, http.MethodPut, "/sys/leases/revoke-prefix/"+prefix, nil, nil)
+}
+
+// ListLeases returns the keys under lease ID 'prefix', like "database/creds/ms-order".
+// Keys ending with '/' are nested prefixes, the others are lease IDs relative to 'prefix'.
+// Instance token needs sudo on sys/leases/lookup.
+func (v *Vault) ListLeases(ctx context.Context, prefix string) (keys []string, err error) {
+	prefix = strings.Trim(prefix, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	var ddd configList
+	err = v.doJSON(ctx, http.MethodGet, "/sys/leases/lookup/"+prefix+"?list=true", nil, &ddd)
+	return ddd.Data.Keys, err
+}
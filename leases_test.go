@@ -0,0 +1,104 @@
+package forest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test_Leases works on token leases, which every service token has without mounting anything.
+// Tokens are created against a token role, so their leases share a prefix only this test uses.
+func Test_Leases(t *testing.T) {
+	ctx := context.TODO()
+	const role = "forest-test-lease"
+	const prefix = "auth/token/create/" + role + "/"
+	require.NoError(t, UpsertTokenRole(ctx, TokenRole{Name: role, AllowedPolicies: []string{"default"}}))
+	defer DeleteTokenRole(ctx, role)
+	require.NoError(t, RevokePrefix(ctx, prefix))
+
+	var tokens []string
+	for i := 0; i < 2; i++ {
+		token, err := CreateNewToken().WithRole(role).WithTimeToLive(time.Hour).Do(ctx)
+		require.NoError(t, err)
+		tokens = append(tokens, token)
+	}
+
+	keys, err := ListLeases(ctx, "/auth/token/create/"+role)
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	ids := []string{prefix + keys[0], prefix + keys[1]}
+
+	t.Run("Lookup", func(t *testing.T) {
+		l, err := LookupLease(ctx, ids[0])
+		require.NoError(t, err)
+		assert.Equal(t, ids[0], l.ID)
+		assert.True(t, l.Renewable)
+		assert.True(t, l.TTL > 59*time.Minute && l.TTL <= time.Hour, "TTL %s", l.TTL)
+		assert.WithinDuration(t, time.Now(), l.IssueTime, time.Minute)
+		assert.WithinDuration(t, l.IssueTime.Add(time.Hour), l.ExpireTime, time.Second)
+		assert.True(t, l.LastRenewal.IsZero())
+	})
+
+	t.Run("Renew", func(t *testing.T) {
+		// Tokens are renewed through auth/token, sys/leases only renews secrets
+		_, err := RenewLease(ctx, ids[0], time.Hour)
+		assert.Error(t, err)
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		require.NoError(t, RevokeLease(ctx, ids[0]))
+		_, err := LookupLease(ctx, ids[0])
+		assert.Error(t, err)
+		keys, err := ListLeases(ctx, prefix)
+		require.NoError(t, err)
+		assert.Equal(t, []string{ids[1][len(prefix):]}, keys)
+	})
+
+	t.Run("Revoke Prefix", func(t *testing.T) {
+		require.NoError(t, RevokePrefix(ctx, "/"+prefix))
+		for _, token := range tokens {
+			_, err := LookupOther(ctx, token)
+			assert.Error(t, err)
+		}
+		keys, _ := ListLeases(ctx, prefix) // Vault answers 404 once nothing is left
+		assert.Empty(t, keys)
+	})
+}
+
+// Test_RenewLease fakes sys/leases/renew, renewable secret leases need an engine backed by an external system
+func Test_RenewLease(t *testing.T) {
+	v, server := fakeVault(t, map[string]http.HandlerFunc{
+		"/sys/leases/renew": func(w http.ResponseWriter, r *http.Request) {
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			assert.Equal(t, http.MethodPut, r.Method)
+			assert.Equal(t, map[string]string{"lease_id": "database/creds/ms-order/abc", "increment": "1h"}, body)
+			w.Write([]byte(`{"lease_id":"database/creds/ms-order/abc","renewable":true,"lease_duration":1800}`))
+		},
+	})
+	defer server.Close()
+
+	l, err := v.RenewLease(context.TODO(), "database/creds/ms-order/abc", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, LeaseInfo{LeaseID: "database/creds/ms-order/abc", Renewable: true, LeaseDuration: 1800}, l)
+	assert.Equal(t, 30*time.Minute, l.TTL())
+}
+
+func Test_Leases_Invalid(t *testing.T) {
+	ctx := context.TODO()
+	_, err := LookupLease(ctx, "forest_kv/does-not-exist")
+	assert.Error(t, err)
+	_, err = LookupLease(ctx, "")
+	assert.Error(t, err)
+	_, err = RenewLease(ctx, "", 0)
+	assert.Error(t, err)
+	_, err = RenewLease(ctx, "auth/token/create/abc", -time.Second)
+	assert.Error(t, err)
+	assert.Error(t, RevokeLease(ctx, ""))
+	assert.Error(t, RevokePrefix(ctx, "/"))
+}
@@ -0,0 +1,287 @@
+package forest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// MountOptions configures a secrets engine mount
+type MountOptions struct {
+	Type            string            // Engine type, like "kv" or "transit". Required when enabling.
+	Description     string            // Human friendly description
+	Version         string            // Engine version, like "2" for kv v2. Shortcut for Options["version"].
+	DefaultLeaseTTL time.Duration     // Zero uses the system default
+	MaxLeaseTTL     time.Duration     // Zero uses the system default
+	Options         map[string]string // Engine specific options
+}
+
+// MountConfig is the tunable configuration of a mount
+type MountConfig struct {
+	DefaultLeaseTTL time.Duration
+	MaxLeaseTTL     time.Duration
+	ForceNoCache    bool
+	Description     string
+	Options         map[string]string
+}
+
+// Version returns the engine version option, empty if not set
+func (c MountConfig) Version() string {
+	return c.Options["version"]
+}
+
+// Mount is an enabled secrets engine or auth method
+type Mount struct {
+	Path        string // Without trailing '/'
+	Type        string
+	Description string
+	Accessor    string
+	Local       bool
+	SealWrap    bool
+	Config      MountConfig
+}
+
+type jsonMountConfig struct {
+	DefaultLeaseTTL int64 `json:"default_lease_ttl"`
+	MaxLeaseTTL     int64 `json:"max_lease_ttl"`
+	ForceNoCache    bool  `json:"force_no_cache"`
+}
+
+type jsonMount struct {
+	Type        string            `json:"type"`
+	Description string            `json:"description"`
+	Accessor    string            `json:"accessor"`
+	Local       bool              `json:"local"`
+	SealWrap    bool              `json:"seal_wrap"`
+	Options     map[string]string `json:"options"`
+	Config      jsonMountConfig   `json:"config"`
+}
+
+func (m jsonMount) toMount(path string) Mount {
+	return Mount{
+		Path:        strings.TrimSuffix(path, "/"),
+		Type:        m.Type,
+		Description: m.Description,
+		Accessor:    m.Accessor,
+		Local:       m.Local,
+		SealWrap:    m.SealWrap,
+		Config: MountConfig{
+			DefaultLeaseTTL: time.Duration(m.Config.DefaultLeaseTTL) * time.Second,
+			MaxLeaseTTL:     time.Duration(m.Config.MaxLeaseTTL) * time.Second,
+			ForceNoCache:    m.Config.ForceNoCache,
+			Description:     m.Description,
+			Options:         m.Options,
+		},
+	}
+}
+
+type mountsResponse struct {
+	RequestID     string               `json:"request_id"`
+	LeaseID       string               `json:"lease_id"`
+	Renewable     bool                 `json:"renewable"`
+	LeaseDuration int                  `json:"lease_duration"`
+	WrapInfo      interface{}          `json:"wrap_info"`
+	Warnings      []string             `json:"warnings"`
+	Data          map[string]jsonMount `json:"data"`
+}
+
+type jsonTune struct {
+	DefaultLeaseTTL string            `json:"default_lease_ttl,omitempty"`
+	MaxLeaseTTL     string            `json:"max_lease_ttl,omitempty"`
+	Description     *string           `json:"description,omitempty"`
+	Options         map[string]string `json:"options,omitempty"`
+}
+
+type jsonEnableMount struct {
+	Type        string            `json:"type"`
+	Description string            `json:"description,omitempty"`
+	Config      jsonTune          `json:"config"`
+	Options     map[string]string `json:"options,omitempty"`
+}
+
+type tuneResponse struct {
+	RequestID     string `json:"request_id"`
+	LeaseID       string `json:"lease_id"`
+	Renewable     bool   `json:"renewable"`
+	LeaseDuration int    `json:"lease_duration"`
+	Data          struct {
+		jsonMountConfig
+		Description string            `json:"description"`
+		Options     map[string]string `json:"options"`
+	} `json:"data"`
+}
+
+// options merges Version into Options
+func (o MountOptions) options() map[string]string {
+	if o.Version == "" {
+		return o.Options
+	}
+	opts := make(map[string]string, len(o.Options)+1)
+	for k, v := range o.Options {
+		opts[k] = v
+	}
+	opts["version"] = o.Version
+	return opts
+}
+
+func (o MountOptions) tune() jsonTune {
+	t := jsonTune{
+		DefaultLeaseTTL: optionalDuration(o.DefaultLeaseTTL),
+		MaxLeaseTTL:     optionalDuration(o.MaxLeaseTTL),
+		Options:         o.options(),
+	}
+	if o.Description != "" {
+		t.Description = &o.Description
+	}
+	return t
+}
+
+func mountPath(path string) (string, error) {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return "", errors.New("mount path cannot be empty")
+	}
+	return path, nil
+}
+
+// ListMounts returns every enabled secrets engine, keyed by path without trailing '/'
+func (v *Vault) ListMounts(ctx context.Context) (mounts map[string]Mount, err error) {
+	return v.listMounts(ctx, "/sys/mounts/")
+}
+
+func (v *Vault) listMounts(ctx context.Context, base string) (mounts map[string]Mount, err error) {
+	var response mountsResponse
+	if err = v.doJSON(ctx, http.MethodGet, strings.TrimSuffix(base, "/"), nil, &response); err != nil {
+		return
+	}
+	mounts = make(map[string]Mount, len(response.Data))
+	for p, m := range response.Data {
+		mount := m.toMount(p)
+		mounts[mount.Path] = mount
+	}
+	return
+}
+
+// EnableMount enables a secrets engine of type opts.Type at 'path'. It fails if the path is already in use.
+//
+// Example: `err := vault.EnableMount(ctx, "forest_kv", forest.MountOptions{Type: "kv", Version: "1"})`
+func (v *Vault) EnableMount(ctx context.Context, path string, opts MountOptions) (err error) {
+	return v.enableMount(ctx, "/sys/mounts/", path, opts)
+}
+
+func (v *Vault) enableMount(ctx context.Context, base, path string, opts MountOptions) (err error) {
+	if path, err = mountPath(path); err != nil {
+		return
+	}
+	if opts.Type == "" {
+		return errors.New("mount type cannot be empty")
+	}
+	body := jsonEnableMount{
+		Type:        opts.Type,
+		Description: opts.Description,
+		Config: jsonTune{
+			DefaultLeaseTTL: optionalDuration(opts.DefaultLeaseTTL),
+			MaxLeaseTTL:     optionalDuration(opts.MaxLeaseTTL),
+		},
+		Options: opts.options(),
+	}
+	return v.doJSON(ctx, http.MethodPost, base+path, body, nil)
+}
+
+// TuneMount updates the configuration of the mount at 'path'. Zero values of opts are left unchanged,
+// and opts.Type is ignored since the type of a mount can't change.
+func (v *Vault) TuneMount(ctx context.Context, path string, opts MountOptions) (err error) {
+	return v.tuneMount(ctx, "/sys/mounts/", path, opts)
+}
+
+func (v *Vault) tuneMount(ctx context.Context, base, path string, opts MountOptions) (err error) {
+	if path, err = mountPath(path); err != nil {
+		return
+	}
+	return v.doJSON(ctx, http.MethodPost, base+path+"/tune", opts.tune(), nil)
+}
+
+// ReadMountConfig returns the tunable configuration of the mount at 'path'
+func (v *Vault) ReadMountConfig(ctx context.Context, path string) (c MountConfig, err error) {
+	return v.readMountConfig(ctx, "/sys/mounts/", path)
+}
+
+func (v *Vault) readMountConfig(ctx context.Context, base, path string) (c MountConfig, err error) {
+	if path, err = mountPath(path); err != nil {
+		return
+	}
+	var response tuneResponse
+	if err = v.doJSON(ctx, http.MethodGet, base+path+"/tune", nil, &response); err != nil {
+		return
+	}
+	return MountConfig{
+		DefaultLeaseTTL: time.Duration(response.Data.DefaultLeaseTTL) * time.Second,
+		MaxLeaseTTL:     time.Duration(response.Data.MaxLeaseTTL) * time.Second,
+		ForceNoCache:    response.Data.ForceNoCache,
+		Description:     response.Data.Description,
+		Options:         response.Data.Options,
+	}, nil
+}
+
+// DisableMount disables the mount at 'path'. Every secret stored in it is deleted and its leases are revoked.
+func (v *Vault) DisableMount(ctx context.Context, path string) (err error) {
+	return v.disableMount(ctx, "/sys/mounts/", path)
+}
+
+func (v *Vault) disableMount(ctx context.Context, base, path string) (err error) {
+	if path, err = mountPath(path); err != nil {
+		return
+	}
+	return v.doJSON(ctx, http.MethodDelete, base+path, nil, nil)
+}
+
+// EnsureMount makes sure a secrets engine of type opts.Type is mounted at 'path' with the given options.
+// It's enabled if missing and tuned if its configuration differs, so it's safe to call on every start.
+// An error is returned if 'path' is used by an engine of a different type, which is never replaced.
+//
+// Example: `created, err := vault.EnsureMount(ctx, "forest_transit_test", forest.MountOptions{Type: "transit"})`
+func (v *Vault) EnsureMount(ctx context.Context, path string, opts MountOptions) (created bool, err error) {
+	return v.ensureMount(ctx, "/sys/mounts/", path, opts)
+}
+
+func (v *Vault) ensureMount(ctx context.Context, base, path string, opts MountOptions) (created bool, err error) {
+	if path, err = mountPath(path); err != nil {
+		return
+	}
+	mounts, err := v.listMounts(ctx, base)
+	if err != nil {
+		return
+	}
+	m, ok := mounts[path]
+	if !ok {
+		return true, v.enableMount(ctx, base, path, opts)
+	}
+	if opts.Type != "" && m.Type != opts.Type {
+		return false, fmt.Errorf("path '%s' is already mounted with type '%s', expecting '%s'", path, m.Type, opts.Type)
+	}
+	if mountNeedsTune(m, opts) {
+		err = v.tuneMount(ctx, base, path, opts)
+	}
+	return
+}
+
+func mountNeedsTune(m Mount, opts MountOptions) bool {
+	if opts.DefaultLeaseTTL > 0 && opts.DefaultLeaseTTL != m.Config.DefaultLeaseTTL {
+		return true
+	}
+	if opts.MaxLeaseTTL > 0 && opts.MaxLeaseTTL != m.Config.MaxLeaseTTL {
+		return true
+	}
+	if opts.Description != "" && opts.Description != m.Description {
+		return true
+	}
+	for k, v := range opts.options() {
+		if m.Config.Options[k] != v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package forest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_MountNeedsTune(t *testing.T) {
+	m := Mount{Description: "orders", Config: MountConfig{DefaultLeaseTTL: time.Hour, Options: map[string]string{"version": "2"}}}
+	assert.False(t, mountNeedsTune(m, MountOptions{}))
+	assert.False(t, mountNeedsTune(m, MountOptions{Type: "kv", Version: "2", DefaultLeaseTTL: time.Hour, Description: "orders"}))
+	assert.True(t, mountNeedsTune(m, MountOptions{Version: "1"}))
+	assert.True(t, mountNeedsTune(m, MountOptions{DefaultLeaseTTL: time.Minute}))
+	assert.True(t, mountNeedsTune(m, MountOptions{MaxLeaseTTL: time.Minute}))
+	assert.True(t, mountNeedsTune(m, MountOptions{Description: "payments"}))
+}
+
+func Test_Mounts(t *testing.T) {
+	ctx := context.TODO()
+	const path = "forest_test_mount"
+	DisableMount(ctx, path)
+
+	t.Run("Ensure Test Engines", func(t *testing.T) {
+		_, err := EnsureMount(ctx, GetKVEngine(), MountOptions{Type: "kv"})
+		require.NoError(t, err)
+		_, err = EnsureMount(ctx, GetTransitEngine(), MountOptions{Type: "transit"})
+		require.NoError(t, err)
+	})
+
+	t.Run("Enable", func(t *testing.T) {
+		created, err := EnsureMount(ctx, path, MountOptions{Type: "kv", Version: "2", Description: "forest test", DefaultLeaseTTL: time.Hour})
+		require.NoError(t, err)
+		assert.True(t, created)
+
+		mounts, err := ListMounts(ctx)
+		require.NoError(t, err)
+		m, ok := mounts[path]
+		require.True(t, ok)
+		assert.Equal(t, "kv", m.Type)
+		assert.Equal(t, "forest test", m.Description)
+		assert.Equal(t, "2", m.Config.Version())
+		assert.Equal(t, time.Hour, m.Config.DefaultLeaseTTL)
+
+		err = EnableMount(ctx, path, MountOptions{Type: "kv"})
+		assert.Error(t, err)
+	})
+
+	t.Run("Ensure Is Idempotent", func(t *testing.T) {
+		created, err := EnsureMount(ctx, path, MountOptions{Type: "kv", Version: "2", DefaultLeaseTTL: time.Hour})
+		require.NoError(t, err)
+		assert.False(t, created)
+
+		_, err = EnsureMount(ctx, path, MountOptions{Type: "transit"})
+		assert.Error(t, err)
+	})
+
+	t.Run("Tune", func(t *testing.T) {
+		_, err := EnsureMount(ctx, path, MountOptions{Type: "kv", MaxLeaseTTL: 2 * time.Hour, Description: "tuned"})
+		require.NoError(t, err)
+		c, err := ReadMountConfig(ctx, path)
+		require.NoError(t, err)
+		assert.Equal(t, time.Hour, c.DefaultLeaseTTL)
+		assert.Equal(t, 2*time.Hour, c.MaxLeaseTTL)
+		assert.Equal(t, "tuned", c.Description)
+	})
+
+	t.Run("Disable", func(t *testing.T) {
+		require.NoError(t, DisableMount(ctx, path))
+		mounts, err := ListMounts(ctx)
+		require.NoError(t, err)
+		assert.NotContains(t, mounts, path)
+	})
+}
@@ -16,6 +16,12 @@ type Config struct {
 	HTTPClient          *http.Client // Uses default http client if nil
 	KeyValueEngine      string
 	TransitEngine       string
+	DatabaseEngine      string // Optional. Path of the database secrets engine. Defaults to database.
+	PKIEngine           string // Optional. Path of the PKI secrets engine. Defaults to pki.
+	AppRoleAuth         string // Optional. Path of the AppRole auth method. Defaults to approle.
+	KubernetesAuth      string // Optional. Path of the Kubernetes auth method. Defaults to kubernetes.
+	HealthStandbyOK     bool   // Optional. Makes Health treat a standby node as healthy.
+	HealthPerfStandbyOK bool   // Optional. Makes Health treat a performance standby node as healthy.
 }
 
 const (
@@ -69,3 +75,45 @@ func WithKeyValueEngine(engine string) OptionFunc {
 		o.KeyValueEngine = engine
 	}
 }
+
+// WithDatabaseEngine sets the path the database secrets engine is mounted at
+func WithDatabaseEngine(engine string) OptionFunc {
+	return func(o *Config) {
+		o.DatabaseEngine = engine
+	}
+}
+
+// WithPKIEngine sets the path the PKI secrets engine is mounted at
+func WithPKIEngine(engine string) OptionFunc {
+	return func(o *Config) {
+		o.PKIEngine = engine
+	}
+}
+
+// WithAppRoleAuth sets the path the AppRole auth method is enabled at
+func WithAppRoleAuth(path string) OptionFunc {
+	return func(o *Config) {
+		o.AppRoleAuth = path
+	}
+}
+
+// WithKubernetesAuth sets the path the Kubernetes auth method is enabled at
+func WithKubernetesAuth(path string) OptionFunc {
+	return func(o *Config) {
+		o.KubernetesAuth = path
+	}
+}
+
+// WithHealthStandbyOK makes Health treat standby nodes as healthy. Useful when requests are forwarded to the active node.
+func WithHealthStandbyOK() OptionFunc {
+	return func(o *Config) {
+		o.HealthStandbyOK = true
+	}
+}
+
+// WithHealthPerfStandbyOK makes Health treat performance standby nodes as healthy
+func WithHealthPerfStandbyOK() OptionFunc {
+	return func(o *Config) {
+		o.HealthPerfStandbyOK = true
+	}
+}
@@ -0,0 +1,267 @@
+package forest
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// CertificateRequest are the parameters of a certificate issued or signed by the PKI engine.
+// The role decides which of them are allowed.
+type CertificateRequest struct {
+	CommonName        string
+	AltNames          []string // DNS names or email addresses
+	IPSANs            []net.IP
+	URISANs           []string
+	TTL               time.Duration // Zero uses the role's default
+	ExcludeCNFromSANs bool
+}
+
+type jsonCertificateRequest struct {
+	CSR               string `json:"csr,omitempty"`
+	CommonName        string `json:"common_name"`
+	AltNames          string `json:"alt_names,omitempty"`
+	IPSANs            string `json:"ip_sans,omitempty"`
+	URISANs           string `json:"uri_sans,omitempty"`
+	TTL               string `json:"ttl,omitempty"`
+	ExcludeCNFromSANs bool   `json:"exclude_cn_from_sans,omitempty"`
+}
+
+func (r CertificateRequest) toJSON() jsonCertificateRequest {
+	ips := make([]string, len(r.IPSANs))
+	for i, ip := range r.IPSANs {
+		ips[i] = ip.String()
+	}
+	return jsonCertificateRequest{
+		CommonName:        r.CommonName,
+		AltNames:          strings.Join(r.AltNames, ","),
+		IPSANs:            strings.Join(ips, ","),
+		URISANs:           strings.Join(r.URISANs, ","),
+		TTL:               optionalDuration(r.TTL),
+		ExcludeCNFromSANs: r.ExcludeCNFromSANs,
+	}
+}
+
+// IssuedCertificate is a certificate issued or signed by the PKI engine
+type IssuedCertificate struct {
+	Certificate    *x509.Certificate
+	CertificatePEM string
+	IssuingCA      string   // PEM
+	CAChain        []string // PEM of each certificate of the chain, issuing CA first
+	PrivateKeyPEM  string   // Empty for signed CSRs. Vault doesn't keep it, so it can't be retrieved again.
+	PrivateKeyType string
+	SerialNumber   string // Colon separated hex, as used by RevokeCertificate
+}
+
+// TLSCertificate returns the certificate and its chain with the private key, ready for tls.Config
+func (c IssuedCertificate) TLSCertificate() (cert tls.Certificate, err error) {
+	if c.PrivateKeyPEM == "" {
+		return cert, errors.New("certificate has no private key")
+	}
+	chain := c.CertificatePEM
+	for _, ca := range c.CAChain {
+		chain += "\n" + ca
+	}
+	if cert, err = tls.X509KeyPair([]byte(chain), []byte(c.PrivateKeyPEM)); err != nil {
+		return
+	}
+	cert.Leaf = c.Certificate
+	return
+}
+
+type issuedCertificateResponse struct {
+	RequestID string `json:"request_id"`
+	Data      struct {
+		Certificate    string   `json:"certificate"`
+		IssuingCA      string   `json:"issuing_ca"`
+		CAChain        []string `json:"ca_chain"`
+		PrivateKey     string   `json:"private_key"`
+		PrivateKeyType string   `json:"private_key_type"`
+		SerialNumber   string   `json:"serial_number"`
+	} `json:"data"`
+}
+
+type pkiCertificateResponse struct {
+	RequestID string `json:"request_id"`
+	Data      struct {
+		Certificate    string `json:"certificate"`
+		RevocationTime int64  `json:"revocation_time"`
+	} `json:"data"`
+}
+
+var errNoCertificate = errors.New("no certificate found in response")
+
+// parseCertificates parses every PEM encoded certificate of 'data'
+func parseCertificates(data string) (certs []*x509.Certificate, err error) {
+	rest := []byte(data)
+	for {
+		var block *pem.Block
+		if block, rest = pem.Decode(rest); block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errNoCertificate
+	}
+	return certs, nil
+}
+
+func (v *Vault) pkiPath(kind, name string) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("PKI %s cannot be empty", kind)
+	}
+	return fmt.Sprintf("/%s/%s/%s", v.Config.PKIEngine, kind, url.PathEscape(name)), nil
+}
+
+func (v *Vault) issue(ctx context.Context, path string, body jsonCertificateRequest) (c IssuedCertificate, err error) {
+	var response issuedCertificateResponse
+	if err = v.doJSON(ctx, http.MethodPost, path, body, &response); err != nil {
+		return
+	}
+	certs, err := parseCertificates(response.Data.Certificate)
+	if err != nil {
+		return
+	}
+	chain := response.Data.CAChain
+	if len(chain) == 0 && response.Data.IssuingCA != "" {
+		chain = []string{response.Data.IssuingCA}
+	}
+	return IssuedCertificate{
+		Certificate:    certs[0],
+		CertificatePEM: response.Data.Certificate,
+		IssuingCA:      response.Data.IssuingCA,
+		CAChain:        chain,
+		PrivateKeyPEM:  response.Data.PrivateKey,
+		PrivateKeyType: response.Data.PrivateKeyType,
+		SerialNumber:   response.Data.SerialNumber,
+	}, nil
+}
+
+// IssueCertificate issues a new certificate and private key from role 'role'
+//
+// Example: `cert, err := vault.IssueCertificate(ctx, "internal", forest.CertificateRequest{CommonName: "ms-order.svc", TTL: 24 * time.Hour})`
+func (v *Vault) IssueCertificate(ctx context.Context, role string, req CertificateRequest) (c IssuedCertificate, err error) {
+	path, err := v.pkiPath("issue", role)
+	if err != nil {
+		return
+	}
+	if req.CommonName == "" {
+		return c, errors.New("common name cannot be empty")
+	}
+	return v.issue(ctx, path, req.toJSON())
+}
+
+// SignCSR signs the PEM encoded certificate signing request 'csr' with role 'role'. The private key never leaves the caller.
+// Fields of 'req' which are set override the ones of the CSR, if the role allows it.
+func (v *Vault) SignCSR(ctx context.Context, role string, csr []byte, req CertificateRequest) (c IssuedCertificate, err error) {
+	path, err := v.pkiPath("sign", role)
+	if err != nil {
+		return
+	}
+	block, _ := pem.Decode(csr)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return c, errors.New("CSR must be PEM encoded")
+	}
+	body := req.toJSON()
+	body.CSR = string(csr)
+	return v.issue(ctx, path, body)
+}
+
+// RevokeCertificate revokes the certificate with serial number 'serial', in colon separated hex. Returns the revocation time.
+func (v *Vault) RevokeCertificate(ctx context.Context, serial string) (revokedAt time.Time, err error) {
+	if serial == "" {
+		return revokedAt, errors.New("serial number cannot be empty")
+	}
+	var response pkiCertificateResponse
+	body := map[string]string{"serial_number": serial}
+	if err = v.doJSON(ctx, http.MethodPost, fmt.Sprintf("/%s/revoke", v.Config.PKIEngine), body, &response); err != nil {
+		return
+	}
+	return time.Unix(response.Data.RevocationTime, 0), nil
+}
+
+// ReadCertificate returns the certificate with serial number 'serial', or one of the special serials "ca" and "ca_chain"
+func (v *Vault) ReadCertificate(ctx context.Context, serial string) (cert *x509.Certificate, err error) {
+	certs, err := v.readCertificates(ctx, serial)
+	if err != nil {
+		return
+	}
+	return certs[0], nil
+}
+
+func (v *Vault) readCertificates(ctx context.Context, serial string) (certs []*x509.Certificate, err error) {
+	path, err := v.pkiPath("cert", serial)
+	if err != nil {
+		return
+	}
+	var response pkiCertificateResponse
+	if err = v.doJSON(ctx, http.MethodGet, path, nil, &response); err != nil {
+		return
+	}
+	return parseCertificates(response.Data.Certificate)
+}
+
+// ReadCAChain returns the CA chain of the engine, issuing CA first
+func (v *Vault) ReadCAChain(ctx context.Context) (chain []*x509.Certificate, err error) {
+	chain, err = v.readCertificates(ctx, "ca_chain")
+	if err == errNoCertificate {
+		// Older Vault versions leave the chain empty when the engine holds a root CA only
+		return v.readCertificates(ctx, "ca")
+	}
+	return
+}
+
+// CAPool returns a pool of the engine's CA chain, to verify certificates it issued
+func (v *Vault) CAPool(ctx context.Context) (pool *x509.CertPool, err error) {
+	chain, err := v.ReadCAChain(ctx)
+	if err != nil {
+		return
+	}
+	pool = x509.NewCertPool()
+	for _, ca := range chain {
+		pool.AddCert(ca)
+	}
+	return
+}
+
+// ReadCRL returns the current certificate revocation list of the engine
+func (v *Vault) ReadCRL(ctx context.Context) (crl *pkix.CertificateList, err error) {
+	var response pkiCertificateResponse
+	if err = v.doJSON(ctx, http.MethodGet, fmt.Sprintf("/%s/cert/crl", v.Config.PKIEngine), nil, &response); err != nil {
+		return
+	}
+	return x509.ParseCRL([]byte(response.Data.Certificate))
+}
+
+// ListCertificates returns serial numbers of every certificate issued by the engine, revoked ones included.
+// Vault lists them hyphen separated, they're returned colon separated like IssuedCertificate.SerialNumber,
+// so they can be passed to RevokeCertificate and ReadCertificate as is.
+func (v *Vault) ListCertificates(ctx context.Context) (serials []string, err error) {
+	var ddd configList
+	err = v.doJSON(ctx, http.MethodGet, fmt.Sprintf("/%s/certs?list=true", v.Config.PKIEngine), nil, &ddd)
+	if err != nil {
+		return
+	}
+	serials = make([]string, len(ddd.Data.Keys))
+	for i, key := range ddd.Data.Keys {
+		serials[i] = strings.Replace(key, "-", ":", -1)
+	}
+	return serials, nil
+}
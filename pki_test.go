@@ -0,0 +1,225 @@
+package forest
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pkiEngine mounts the PKI engine with a root CA, and creates role 'role' issuing localhost and *.svc certificates.
+// The root CA is generated on the first run only, later runs reuse it.
+func pkiEngine(t *testing.T, role string) {
+	ctx := context.TODO()
+	engine := GetConfigInstance().PKIEngine
+	_, err := EnsureMount(ctx, engine, MountOptions{Type: "pki", MaxLeaseTTL: 87600 * time.Hour})
+	require.NoError(t, err)
+	if _, err = ReadCAChain(ctx); err != nil {
+		err = gVault.doJSON(ctx, http.MethodPost, "/"+engine+"/root/generate/internal", map[string]interface{}{
+			"common_name": "forest test CA", "ttl": "87600h", "key_type": "ec", "key_bits": 256,
+		}, nil)
+		require.NoError(t, err)
+	}
+	err = gVault.doJSON(ctx, http.MethodPost, "/"+engine+"/roles/"+role, map[string]interface{}{
+		"allowed_domains": []string{"svc", "localhost"}, "allow_subdomains": true, "allow_bare_domains": true,
+		"allow_localhost": true, "allow_ip_sans": true, "key_type": "ec", "key_bits": 256, "max_ttl": "1h",
+	}, nil)
+	require.NoError(t, err)
+}
+
+func deletePKIRole(role string) {
+	gVault.doJSON(context.TODO(), http.MethodDelete, "/"+GetConfigInstance().PKIEngine+"/roles/"+role, nil, nil)
+}
+
+// serialOf parses a serial number in the colon separated hex format used by Vault
+func serialOf(t *testing.T, serial string) *big.Int {
+	n, ok := new(big.Int).SetString(strings.Replace(serial, ":", "", -1), 16)
+	require.True(t, ok, serial)
+	return n
+}
+
+func Test_IssueCertificate(t *testing.T) {
+	ctx := context.TODO()
+	pkiEngine(t, "internal")
+
+	issued, err := IssueCertificate(ctx, "internal", CertificateRequest{
+		CommonName: "ms-order.svc",
+		AltNames:   []string{"orders.svc"},
+		IPSANs:     []net.IP{net.ParseIP("10.0.0.1")},
+		TTL:        10 * time.Minute,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "ms-order.svc", issued.Certificate.Subject.CommonName)
+	assert.ElementsMatch(t, []string{"ms-order.svc", "orders.svc"}, issued.Certificate.DNSNames)
+	require.Len(t, issued.Certificate.IPAddresses, 1)
+	assert.True(t, issued.Certificate.IPAddresses[0].Equal(net.ParseIP("10.0.0.1")))
+	assert.WithinDuration(t, time.Now().Add(10*time.Minute), issued.Certificate.NotAfter, time.Minute)
+	assert.Equal(t, issued.Certificate.SerialNumber, serialOf(t, issued.SerialNumber))
+	assert.NotEmpty(t, issued.CAChain)
+	assert.Equal(t, "ec", issued.PrivateKeyType)
+
+	cert, err := issued.TLSCertificate()
+	require.NoError(t, err)
+	assert.Len(t, cert.Certificate, 1+len(issued.CAChain))
+	assert.Equal(t, issued.Certificate, cert.Leaf)
+
+	pool, err := CAPool(ctx)
+	require.NoError(t, err)
+	_, err = issued.Certificate.Verify(x509.VerifyOptions{Roots: pool, DNSName: "orders.svc"})
+	assert.NoError(t, err)
+
+	read, err := ReadCertificate(ctx, issued.SerialNumber)
+	require.NoError(t, err)
+	assert.Equal(t, issued.Certificate.Raw, read.Raw)
+
+	_, err = IssueCertificate(ctx, "internal", CertificateRequest{})
+	assert.Error(t, err)
+	_, err = IssueCertificate(ctx, "internal", CertificateRequest{CommonName: "example.com"})
+	assert.Error(t, err)
+	_, err = IssueCertificate(ctx, "does-not-exist", CertificateRequest{CommonName: "ms-order.svc"})
+	assert.Error(t, err)
+}
+
+func Test_SignCSR(t *testing.T) {
+	ctx := context.TODO()
+	pkiEngine(t, "internal")
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "ms-user.svc"}}, key)
+	require.NoError(t, err)
+	csr := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})
+
+	issued, err := SignCSR(ctx, "internal", csr, CertificateRequest{CommonName: "ms-user.svc", TTL: 10 * time.Minute})
+	require.NoError(t, err)
+	assert.Equal(t, "ms-user.svc", issued.Certificate.Subject.CommonName)
+	assert.Equal(t, &key.PublicKey, issued.Certificate.PublicKey)
+	assert.Empty(t, issued.PrivateKeyPEM)
+	_, err = issued.TLSCertificate()
+	assert.Error(t, err)
+
+	_, err = SignCSR(ctx, "internal", der, CertificateRequest{})
+	assert.Error(t, err)
+}
+
+func Test_RevokeCertificate(t *testing.T) {
+	ctx := context.TODO()
+	pkiEngine(t, "internal")
+
+	issued, err := IssueCertificate(ctx, "internal", CertificateRequest{CommonName: "revoked.svc", TTL: 10 * time.Minute})
+	require.NoError(t, err)
+
+	serials, err := ListCertificates(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, serials, issued.SerialNumber)
+	read, err := ReadCertificate(ctx, serials[0])
+	require.NoError(t, err)
+	assert.Equal(t, serialOf(t, serials[0]), read.SerialNumber)
+
+	revokedAt, err := RevokeCertificate(ctx, issued.SerialNumber)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), revokedAt, time.Minute)
+	_, err = RevokeCertificate(ctx, "")
+	assert.Error(t, err)
+
+	crl, err := ReadCRL(ctx)
+	require.NoError(t, err)
+	found := false
+	for _, r := range crl.TBSCertList.RevokedCertificates {
+		found = found || r.SerialNumber.Cmp(issued.Certificate.SerialNumber) == 0
+	}
+	assert.True(t, found, "serial %s not in CRL", issued.SerialNumber)
+}
+
+func Test_ListCertificates_Serials(t *testing.T) {
+	v, server := fakeVault(t, map[string]http.HandlerFunc{
+		"/forest_pki/certs": func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"data":{"keys":["17-67-16-b0-b9-45-58-c0-3a-29-e3-cb-d6-98-33-7a-a6-3c-33-c0"]}}`))
+		},
+	}, WithPKIEngine("forest_pki"))
+	defer server.Close()
+	serials, err := v.ListCertificates(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"17:67:16:b0:b9:45:58:c0:3a:29:e3:cb:d6:98:33:7a:a6:3c:33:c0"}, serials)
+}
+
+func Test_TLSConfig(t *testing.T) {
+	const role = "forest-tls"
+	pkiEngine(t, role)
+	defer deletePKIRole(role)
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	_, err := TLSConfig(ctx, role, CertificateRequest{CommonName: "localhost"}, TLSOptions{RenewFraction: 2})
+	assert.Error(t, err)
+
+	var failures int32
+	conf, err := TLSConfig(ctx, role, CertificateRequest{CommonName: "localhost", TTL: 4 * time.Second}, TLSOptions{
+		RequireClientCert: true,
+		RetryInterval:     200 * time.Millisecond,
+		OnError:           func(error) { atomic.AddInt32(&failures, 1) },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, conf.ClientAuth)
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", conf)
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}
+	}()
+
+	// Both sides share the config, so the handshake verifies server and client certificates against the CA
+	client := conf.Clone()
+	client.ServerName = "localhost"
+	conn, err := tls.Dial("tcp", listener.Addr().String(), client)
+	require.NoError(t, err)
+	conn.Close()
+
+	current := func() *tls.Certificate {
+		cert, err := conf.GetCertificate(nil)
+		require.NoError(t, err)
+		return cert
+	}
+	first := current()
+	assert.Equal(t, first, current())
+
+	// Re-issued in the background once 2/3 of the validity has passed
+	for current() == first && time.Now().Before(first.Leaf.NotAfter) {
+		time.Sleep(100 * time.Millisecond)
+	}
+	renewed := current()
+	require.NotEqual(t, first.Leaf.SerialNumber, renewed.Leaf.SerialNumber)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&failures))
+
+	// Failures are retried in the background, handshakes keep the current certificate meanwhile
+	deletePKIRole(role)
+	for atomic.LoadInt32(&failures) < 2 && time.Now().Before(renewed.Leaf.NotAfter) {
+		time.Sleep(100 * time.Millisecond)
+	}
+	assert.GreaterOrEqual(t, atomic.LoadInt32(&failures), int32(2))
+	if cert, err := conf.GetCertificate(nil); err == nil {
+		assert.Equal(t, renewed, cert)
+	}
+}
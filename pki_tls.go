@@ -0,0 +1,137 @@
+package forest
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"sync"
+	"time"
+)
+
+// TLSOptions configures TLSConfig
+type TLSOptions struct {
+	// RenewFraction of the certificate validity after which it's re-issued. Defaults to 2/3.
+	RenewFraction float64
+	// RetryInterval between failed re-issues. Defaults to 5 seconds.
+	RetryInterval time.Duration
+	// RequireClientCert makes servers require client certificates issued by the engine, for mTLS
+	RequireClientCert bool
+	// OnError is called when re-issuing fails. The current certificate keeps being served until it expires. Optional.
+	OnError func(err error)
+}
+
+// autoCertificate holds a certificate, re-issued in the background before it expires
+type autoCertificate struct {
+	vault   *Vault
+	role    string
+	req     CertificateRequest
+	opts    TLSOptions
+	mu      sync.RWMutex
+	cert    *tls.Certificate
+	renewAt time.Time
+}
+
+func (a *autoCertificate) issue(ctx context.Context) error {
+	issued, err := a.vault.IssueCertificate(ctx, a.role, a.req)
+	if err != nil {
+		return err
+	}
+	cert, err := issued.TLSCertificate()
+	if err != nil {
+		return err
+	}
+	// Based on the time left rather than NotBefore, which Vault backdates
+	now := time.Now()
+	left := issued.Certificate.NotAfter.Sub(now)
+	if left <= 0 {
+		return errors.New("issued certificate has already expired")
+	}
+	a.mu.Lock()
+	a.cert = &cert
+	a.renewAt = now.Add(time.Duration(float64(left) * a.opts.RenewFraction))
+	a.mu.Unlock()
+	return nil
+}
+
+// run re-issues the certificate at its renewal time until ctx is done, retrying every RetryInterval on failure
+func (a *autoCertificate) run(ctx context.Context) {
+	for {
+		a.mu.RLock()
+		wait := time.Until(a.renewAt)
+		a.mu.RUnlock()
+		if !sleepContext(ctx, wait) {
+			return
+		}
+		err := a.issue(ctx)
+		if err == nil {
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if a.opts.OnError != nil {
+			a.opts.OnError(err)
+		}
+		a.mu.Lock()
+		a.renewAt = time.Now().Add(a.opts.RetryInterval)
+		a.mu.Unlock()
+	}
+}
+
+// get returns the current certificate without waiting on Vault, as long as it hasn't expired
+func (a *autoCertificate) get() (*tls.Certificate, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if !time.Now().Before(a.cert.Leaf.NotAfter) {
+		return nil, errors.New("certificate has expired and could not be re-issued")
+	}
+	return a.cert, nil
+}
+
+// TLSConfig issues a certificate from role 'role' and returns a tls.Config serving it, usable by both servers and clients.
+// The certificate is re-issued in the background once RenewFraction of its validity has passed, so handshakes never
+// wait on Vault. If re-issuing fails, it's retried every RetryInterval while the current certificate is still served.
+// The engine's CA chain is trusted for both server and client certificates.
+//
+// Re-issuing stops when ctx is cancelled, so it should live as long as the config is in use.
+//
+// Example:
+//
+//	conf, err := vault.TLSConfig(ctx, "internal", forest.CertificateRequest{CommonName: "ms-order.svc", TTL: 24 * time.Hour}, forest.TLSOptions{RequireClientCert: true})
+//	server := &http.Server{Addr: ":8443", TLSConfig: conf}
+//	server.ListenAndServeTLS("", "")
+func (v *Vault) TLSConfig(ctx context.Context, role string, req CertificateRequest, opts TLSOptions) (*tls.Config, error) {
+	if opts.RenewFraction == 0 {
+		opts.RenewFraction = 2.0 / 3
+	}
+	if opts.RenewFraction <= 0 || opts.RenewFraction >= 1 {
+		return nil, errors.New("renew fraction must be between 0 and 1")
+	}
+	if opts.RetryInterval == 0 {
+		opts.RetryInterval = 5 * time.Second
+	}
+	pool, err := v.CAPool(ctx)
+	if err != nil {
+		return nil, err
+	}
+	auto := &autoCertificate{vault: v, role: role, req: req, opts: opts}
+	if err := auto.issue(ctx); err != nil {
+		return nil, err
+	}
+	go auto.run(ctx)
+	conf := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		RootCAs:    pool,
+		ClientCAs:  pool,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return auto.get()
+		},
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return auto.get()
+		},
+	}
+	if opts.RequireClientCert {
+		conf.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return conf, nil
+}
@@ -0,0 +1,348 @@
+package forest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PolicyEvaluator answers which capabilities a set of policies grants on a path, without a Vault server.
+// It follows Vault's matching rules: a trailing '*' matches any suffix, a '+' segment matches exactly one
+// path segment, and when several rules match, only the most specific one applies. Rules of the same path
+// in different policies are merged, and deny takes precedence over every other capability.
+//
+// Only capabilities are evaluated. Parameter constraints and wrapping TTLs are not taken into account.
+type PolicyEvaluator struct {
+	policies []Policy
+	rules    []PathRule          // One merged rule per path pattern
+	sources  map[string][]string // Path pattern to names of the policies declaring it
+}
+
+// NewPolicyEvaluator creates an evaluator of the union of 'policies', as if they were attached to the same token
+func NewPolicyEvaluator(policies ...Policy) *PolicyEvaluator {
+	e := &PolicyEvaluator{
+		policies: policies,
+		sources:  make(map[string][]string),
+	}
+	index := make(map[string]int)
+	for _, p := range policies {
+		for _, r := range p.Paths {
+			path := strings.TrimPrefix(r.Path, "/")
+			if !containsString(e.sources[path], p.Name) {
+				e.sources[path] = append(e.sources[path], p.Name)
+			}
+			i, ok := index[path]
+			if !ok {
+				index[path] = len(e.rules)
+				r.Path = path
+				r.Capabilities = mergeCapabilities(nil, r.Capabilities)
+				e.rules = append(e.rules, r)
+				continue
+			}
+			e.rules[i].Capabilities = mergeCapabilities(e.rules[i].Capabilities, r.Capabilities)
+		}
+	}
+	return e
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func mergeCapabilities(dst, src []Capability) []Capability {
+	for _, c := range src {
+		found := false
+		for _, have := range dst {
+			found = found || have == c
+		}
+		if !found {
+			dst = append(dst, c)
+		}
+	}
+	return dst
+}
+
+// Match returns the rule which applies to 'path', with capabilities merged across policies.
+// ok is false if no rule matches, in which case every access is denied.
+func (e *PolicyEvaluator) Match(path string) (rule PathRule, ok bool) {
+	path = strings.TrimPrefix(path, "/")
+	for _, r := range e.rules {
+		if !matchPolicyPath(r.Path, path) {
+			continue
+		}
+		if !ok || higherPriority(r.Path, rule.Path) {
+			rule, ok = r, true
+		}
+	}
+	return
+}
+
+// Capabilities returns the effective capabilities on 'path'. It's [deny] if no rule matches or the matching rule denies.
+func (e *PolicyEvaluator) Capabilities(path string) []Capability {
+	rule, ok := e.Match(path)
+	if !ok || len(rule.Capabilities) == 0 {
+		return []Capability{CapabilityDeny}
+	}
+	for _, c := range rule.Capabilities {
+		if c == CapabilityDeny {
+			return []Capability{CapabilityDeny}
+		}
+	}
+	return rule.Capabilities
+}
+
+// Evaluate returns the effective capabilities on each of 'paths', in the same shape as CapabilitiesSelf
+//
+// Example: `caps := forest.NewPolicyEvaluator(p).Evaluate("kv/ms-order-conf"); caps.Can("kv/ms-order-conf", forest.CapabilityRead)`
+func (e *PolicyEvaluator) Evaluate(paths ...string) CapabilityMap {
+	m := make(CapabilityMap, len(paths))
+	for _, p := range paths {
+		m[p] = e.Capabilities(p)
+	}
+	return m
+}
+
+// splitPolicyPath splits a path pattern into its segments, and whether it ends with a '*' glob
+func splitPolicyPath(pattern string) (segments []string, glob bool) {
+	if strings.HasSuffix(pattern, "*") {
+		pattern, glob = strings.TrimSuffix(pattern, "*"), true
+	}
+	return strings.Split(pattern, "/"), glob
+}
+
+// matchPolicyPath reports whether 'pattern' matches 'path' the way Vault does
+func matchPolicyPath(pattern, path string) bool {
+	if !strings.Contains(pattern, "+") {
+		if strings.HasSuffix(pattern, "*") {
+			return strings.HasPrefix(path, strings.TrimSuffix(pattern, "*"))
+		}
+		return pattern == path
+	}
+	want, glob := splitPolicyPath(pattern)
+	have := strings.Split(path, "/")
+	if len(have) < len(want) || (!glob && len(have) != len(want)) {
+		return false
+	}
+	last := len(want) - 1
+	for i, w := range want {
+		switch {
+		case w == "+":
+		case glob && i == last:
+			if !strings.HasPrefix(have[i], w) {
+				return false
+			}
+		case w != have[i]:
+			return false
+		}
+	}
+	return true
+}
+
+// firstWildcard returns the position of the first '+' segment or trailing '*' of pattern, or its length if none
+func firstWildcard(pattern string) int {
+	pos := len(pattern)
+	if strings.HasSuffix(pattern, "*") {
+		pos = len(pattern) - 1
+	}
+	offset := 0
+	for _, seg := range strings.Split(pattern, "/") {
+		if seg == "+" {
+			if offset < pos {
+				pos = offset
+			}
+			break
+		}
+		offset += len(seg) + 1
+	}
+	return pos
+}
+
+func countPlus(pattern string) (n int) {
+	for _, seg := range strings.Split(pattern, "/") {
+		if seg == "+" {
+			n++
+		}
+	}
+	return
+}
+
+// higherPriority reports whether pattern a takes precedence over pattern b when both match a path.
+// From Vault documentation, b is lower priority if, in order:
+// its first wildcard occurs earlier, it ends in '*' and a doesn't, it has more '+' segments, it's shorter,
+// or it's smaller lexicographically.
+func higherPriority(a, b string) bool {
+	if wa, wb := firstWildcard(a), firstWildcard(b); wa != wb {
+		return wa > wb
+	}
+	if ga, gb := strings.HasSuffix(a, "*"), strings.HasSuffix(b, "*"); ga != gb {
+		return gb
+	}
+	if pa, pb := countPlus(a), countPlus(b); pa != pb {
+		return pa < pb
+	}
+	if len(a) != len(b) {
+		return len(a) > len(b)
+	}
+	return a > b
+}
+
+// LintKind is the kind of problem found by PolicyEvaluator.Lint
+type LintKind string
+
+const (
+	// LintDuplicate means the same path is declared more than once in a policy. Vault merges them silently.
+	LintDuplicate LintKind = "duplicate"
+	// LintOverlap means a broader rule's capabilities do not apply to paths matched by a more specific rule.
+	// Vault does not merge overlapping rules, only the most specific one applies.
+	LintOverlap LintKind = "overlap"
+	// LintUnreachable means the rule can't match what it's meant to, because a wildcard is used in a position
+	// where Vault takes it literally
+	LintUnreachable LintKind = "unreachable"
+	// LintNoEffect means the rule or some of its capabilities never grant anything
+	LintNoEffect LintKind = "no-effect"
+)
+
+// PolicyWarning is a problem found by PolicyEvaluator.Lint
+type PolicyWarning struct {
+	Kind    LintKind
+	Policy  string // Comma separated names of the policies declaring the rule
+	Path    string
+	Message string
+}
+
+func (w PolicyWarning) String() string {
+	return fmt.Sprintf("%s: policy '%s', path '%s': %s", w.Kind, w.Policy, w.Path, w.Message)
+}
+
+// Lint returns warnings for rules which most likely don't do what their author meant, ordered by path then kind
+func (e *PolicyEvaluator) Lint() (warnings []PolicyWarning) {
+	warn := func(kind LintKind, path, format string, args ...interface{}) {
+		warnings = append(warnings, PolicyWarning{
+			Kind:    kind,
+			Policy:  strings.Join(e.sources[path], ","),
+			Path:    path,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	for _, p := range e.policies {
+		seen := make(map[string]int)
+		for _, r := range p.Paths {
+			seen[strings.TrimPrefix(r.Path, "/")]++
+		}
+		for path, n := range seen {
+			if n > 1 {
+				warnings = append(warnings, PolicyWarning{
+					Kind:    LintDuplicate,
+					Policy:  p.Name,
+					Path:    path,
+					Message: fmt.Sprintf("declared %d times, capabilities are merged", n),
+				})
+			}
+		}
+	}
+
+	for _, r := range e.rules {
+		segments, _ := splitPolicyPath(r.Path)
+		for _, seg := range segments {
+			if seg != "+" && strings.Contains(seg, "+") {
+				warn(LintUnreachable, r.Path, "'+' only matches a whole segment, '%s' is taken literally", seg)
+			}
+			if strings.Contains(seg, "*") {
+				warn(LintUnreachable, r.Path, "'*' is only a glob at the end of the path, '%s' is taken literally", seg)
+			}
+		}
+
+		if len(r.Capabilities) == 0 {
+			warn(LintNoEffect, r.Path, "no capabilities, every access is denied")
+		}
+		for _, c := range r.Capabilities {
+			if c == CapabilityDeny && len(r.Capabilities) > 1 {
+				warn(LintNoEffect, r.Path, "deny overrides the other capabilities %v", r.Capabilities)
+				break
+			}
+		}
+
+		for _, other := range e.rules {
+			if other.Path == r.Path || !higherPriority(other.Path, r.Path) {
+				continue
+			}
+			example, ok := overlapExample(r.Path, other.Path)
+			if !ok {
+				continue
+			}
+			var lost []Capability
+			for _, c := range r.Capabilities {
+				if !containsCapability(other.Capabilities, c) && !containsCapability(other.Capabilities, CapabilityDeny) {
+					lost = append(lost, c)
+				}
+			}
+			if len(lost) > 0 {
+				warn(LintOverlap, r.Path, "%v do not apply to paths matched by '%s', e.g. '%s'", lost, other.Path, example)
+			}
+		}
+	}
+
+	sort.SliceStable(warnings, func(i, j int) bool {
+		if warnings[i].Path != warnings[j].Path {
+			return warnings[i].Path < warnings[j].Path
+		}
+		return warnings[i].Kind < warnings[j].Kind
+	})
+	return warnings
+}
+
+func containsCapability(list []Capability, c Capability) bool {
+	for _, item := range list {
+		if item == c {
+			return true
+		}
+	}
+	return false
+}
+
+// overlapExample returns a path matched by both patterns, if it can find one
+func overlapExample(a, b string) (string, bool) {
+	for _, candidate := range []string{fillPattern(a, b), fillPattern(b, a)} {
+		for _, suffix := range []string{"", "x", "/x"} {
+			path := candidate + suffix
+			if matchPolicyPath(a, path) && matchPolicyPath(b, path) {
+				return path, true
+			}
+		}
+	}
+	return "", false
+}
+
+// fillPattern turns pattern 'a' into a concrete path, borrowing segments of 'b' for its wildcards
+func fillPattern(a, b string) string {
+	as, aGlob := splitPolicyPath(a)
+	bs, _ := splitPolicyPath(b)
+	borrow := func(i int) string {
+		if i < len(bs) && bs[i] != "+" && bs[i] != "" {
+			return bs[i]
+		}
+		return "x"
+	}
+	path := make([]string, 0, len(bs))
+	for i, seg := range as {
+		switch {
+		case seg == "+":
+			seg = borrow(i)
+		case aGlob && i == len(as)-1 && i < len(bs) && strings.HasPrefix(bs[i], seg) && bs[i] != "+":
+			seg = bs[i]
+		}
+		path = append(path, seg)
+	}
+	if aGlob {
+		for i := len(as); i < len(bs); i++ {
+			path = append(path, borrow(i))
+		}
+	}
+	return strings.Join(path, "/")
+}
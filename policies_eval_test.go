@@ -0,0 +1,114 @@
+package forest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_MatchPolicyPath(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"kv/ms-order-conf", "kv/ms-order-conf", true},
+		{"kv/ms-order-conf", "kv/ms-order-conf/x", false},
+		{"kv/ms-order-*", "kv/ms-order-conf", true},
+		{"kv/ms-order-*", "kv/ms-order-conf/nested", true},
+		{"kv/*", "kv", false},
+		{"kv/+/conf", "kv/ms-order/conf", true},
+		{"kv/+/conf", "kv/ms-order/nested/conf", false},
+		{"kv/+/conf*", "kv/ms-order/config/x", true},
+		{"kv/+", "kv/a/b", false},
+		{"kv/+/*", "kv/a/b/c", true},
+		{"kv/+/*", "kv/a", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, matchPolicyPath(tt.pattern, tt.path), "%s ~ %s", tt.pattern, tt.path)
+	}
+}
+
+func Test_HigherPriority(t *testing.T) {
+	// Ordered from highest to lowest priority
+	ordered := []string{
+		"secret/foo/bar",
+		"secret/foo/b*",
+		"secret/foo/*",
+		"secret/foo",
+		"secret/fo*",
+		"secret/+/bar",
+		"secret/+/+",
+		"secret/*",
+		"secret/+/*",
+	}
+	for i := range ordered {
+		for j := i + 1; j < len(ordered); j++ {
+			assert.True(t, higherPriority(ordered[i], ordered[j]), "%s > %s", ordered[i], ordered[j])
+			assert.False(t, higherPriority(ordered[j], ordered[i]), "%s < %s", ordered[j], ordered[i])
+		}
+	}
+}
+
+func Test_PolicyEvaluator(t *testing.T) {
+	base := Policy{Name: "base", Paths: []PathRule{
+		{Path: "kv/*", Capabilities: []Capability{CapabilityList}},
+		{Path: "kv/+/conf", Capabilities: []Capability{CapabilityRead}},
+		{Path: "transit/encrypt/pii", Capabilities: []Capability{CapabilityUpdate}},
+	}}
+	extra := Policy{Name: "extra", Paths: []PathRule{
+		{Path: "/kv/+/conf", Capabilities: []Capability{CapabilityUpdate}},
+		{Path: "transit/encrypt/pii", Capabilities: []Capability{CapabilityDeny}},
+	}}
+	e := NewPolicyEvaluator(base, extra)
+
+	caps := e.Evaluate("kv/ms-order/conf", "kv/ms-order/other", "transit/encrypt/pii", "sys/mounts")
+	assert.Equal(t, CapabilityMap{
+		"kv/ms-order/conf":    {CapabilityRead, CapabilityUpdate},
+		"kv/ms-order/other":   {CapabilityList},
+		"transit/encrypt/pii": {CapabilityDeny},
+		"sys/mounts":          {CapabilityDeny},
+	}, caps)
+	assert.False(t, caps.Can("kv/ms-order/conf", CapabilityList))
+
+	rule, ok := e.Match("/kv/ms-order/conf")
+	require.True(t, ok)
+	assert.Equal(t, "kv/+/conf", rule.Path)
+	_, ok = e.Match("sys/mounts")
+	assert.False(t, ok)
+}
+
+func Test_PolicyEvaluator_Lint(t *testing.T) {
+	p := Policy{Name: "lint", Paths: []PathRule{
+		{Path: "kv/*", Capabilities: []Capability{CapabilityRead, CapabilityList}},
+		{Path: "kv/ms-order", Capabilities: []Capability{CapabilityUpdate}},
+		{Path: "kv/ms-order", Capabilities: []Capability{CapabilityRead}},
+		{Path: "kv/*/conf", Capabilities: []Capability{CapabilityRead}},
+		{Path: "kv/a+b", Capabilities: []Capability{CapabilityRead}},
+		{Path: "transit/*", Capabilities: []Capability{CapabilityDeny, CapabilityRead}},
+		{Path: "sys/mounts", Capabilities: []Capability{}},
+	}}
+	warnings := NewPolicyEvaluator(p).Lint()
+
+	kinds := make(map[string][]LintKind)
+	for _, w := range warnings {
+		assert.Equal(t, "lint", w.Policy)
+		kinds[w.Path] = append(kinds[w.Path], w.Kind)
+	}
+	assert.Equal(t, map[string][]LintKind{
+		"kv/*":        {LintOverlap, LintOverlap, LintOverlap},
+		"kv/*/conf":   {LintUnreachable},
+		"kv/a+b":      {LintUnreachable},
+		"kv/ms-order": {LintDuplicate},
+		"transit/*":   {LintNoEffect},
+		"sys/mounts":  {LintNoEffect},
+	}, kinds)
+	assert.Contains(t, warnings[0].String(), "overlap: policy 'lint', path 'kv/*'")
+
+	clean := Policy{Name: "clean", Paths: []PathRule{
+		{Path: "kv/*", Capabilities: []Capability{CapabilityRead}},
+		{Path: "kv/ms-order", Capabilities: []Capability{CapabilityRead, CapabilityUpdate}},
+	}}
+	assert.Empty(t, NewPolicyEvaluator(clean).Lint())
+}
@@ -0,0 +1,255 @@
+package forest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// CapabilityDeny Disallows access. Takes precedence over every other capability, including sudo.
+	CapabilityDeny Capability = "deny"
+	// CapabilitySudo Allows access to root protected paths. Used for Policy Creation
+	CapabilitySudo Capability = "sudo"
+	// CapabilityPatch Determines if the token can partially update a resource. Used for Policy Creation
+	CapabilityPatch Capability = "patch"
+)
+
+// Policy is a structured ACL policy. Use ParsePolicy to read one from text, and HCL to render it back.
+type Policy struct {
+	Name  string
+	Paths []PathRule // Kept in the order they are written
+}
+
+// PathRule is a single path block of a policy
+type PathRule struct {
+	Path               string
+	Capabilities       []Capability
+	RequiredParameters []string
+	AllowedParameters  map[string][]interface{} // Parameter name to allowed values. Empty list allows any value.
+	DeniedParameters   map[string][]interface{} // Parameter name to denied values. Empty list denies any value.
+	MinWrappingTTL     time.Duration
+	MaxWrappingTTL     time.Duration
+}
+
+// Rule returns the first rule of given path, and whether it exists
+func (p Policy) Rule(path string) (PathRule, bool) {
+	for _, r := range p.Paths {
+		if r.Path == path {
+			return r, true
+		}
+	}
+	return PathRule{}, false
+}
+
+// SetRule replaces the first rule with the same path, or appends it if there's none
+func (p *Policy) SetRule(rule PathRule) {
+	for i, r := range p.Paths {
+		if r.Path == rule.Path {
+			p.Paths[i] = rule
+			return
+		}
+	}
+	p.Paths = append(p.Paths, rule)
+}
+
+// RemoveRule removes every rule of given path
+func (p *Policy) RemoveRule(path string) {
+	paths := p.Paths[:0]
+	for _, r := range p.Paths {
+		if r.Path != path {
+			paths = append(paths, r)
+		}
+	}
+	p.Paths = paths
+}
+
+// HCL renders the policy in Vault's HCL syntax
+func (p Policy) HCL() string {
+	var sb strings.Builder
+	for i, r := range p.Paths {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		fmt.Fprintf(&sb, "path %s {\n", strconv.Quote(r.Path))
+		caps := make([]string, len(r.Capabilities))
+		for i, c := range r.Capabilities {
+			caps[i] = string(c)
+		}
+		fmt.Fprintf(&sb, "  capabilities = %s\n", hclStringList(caps))
+		if len(r.RequiredParameters) > 0 {
+			fmt.Fprintf(&sb, "  required_parameters = %s\n", hclStringList(r.RequiredParameters))
+		}
+		writeHCLParameters(&sb, "allowed_parameters", r.AllowedParameters)
+		writeHCLParameters(&sb, "denied_parameters", r.DeniedParameters)
+		if r.MinWrappingTTL > 0 {
+			fmt.Fprintf(&sb, "  min_wrapping_ttl = %q\n", formatDuration(r.MinWrappingTTL))
+		}
+		if r.MaxWrappingTTL > 0 {
+			fmt.Fprintf(&sb, "  max_wrapping_ttl = %q\n", formatDuration(r.MaxWrappingTTL))
+		}
+		sb.WriteString("}\n")
+	}
+	return sb.String()
+}
+
+func hclStringList(list []string) string {
+	quoted := make([]string, len(list))
+	for i, s := range list {
+		quoted[i] = strconv.Quote(s)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+func writeHCLParameters(sb *strings.Builder, name string, params map[string][]interface{}) {
+	if len(params) == 0 {
+		return
+	}
+	fmt.Fprintf(sb, "  %s = {\n", name)
+	for _, key := range sortedKeys(params) {
+		values := make([]string, len(params[key]))
+		for i, v := range params[key] {
+			values[i] = hclValue(v)
+		}
+		fmt.Fprintf(sb, "    %s = [%s]\n", strconv.Quote(key), strings.Join(values, ", "))
+	}
+	sb.WriteString("  }\n")
+}
+
+func hclValue(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return strconv.Quote(t)
+	case json.Number:
+		return t.String()
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+func sortedKeys(m map[string][]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type jsonPathRule struct {
+	Capabilities       []Capability             `json:"capabilities"`
+	RequiredParameters []string                 `json:"required_parameters,omitempty"`
+	AllowedParameters  map[string][]interface{} `json:"allowed_parameters,omitempty"`
+	DeniedParameters   map[string][]interface{} `json:"denied_parameters,omitempty"`
+	MinWrappingTTL     string                   `json:"min_wrapping_ttl,omitempty"`
+	MaxWrappingTTL     string                   `json:"max_wrapping_ttl,omitempty"`
+}
+
+// JSON renders the policy in Vault's JSON syntax. Rules of the same path are merged like Vault does, since JSON keys must be unique.
+func (p Policy) JSON() ([]byte, error) {
+	rules := make(map[string]PathRule, len(p.Paths))
+	for _, r := range p.Paths {
+		if existing, ok := rules[r.Path]; ok {
+			r = mergeRules(existing, r)
+		}
+		rules[r.Path] = r
+	}
+	paths := make(map[string]jsonPathRule, len(rules))
+	for _, r := range rules {
+		jr := jsonPathRule{
+			Capabilities:       r.Capabilities,
+			RequiredParameters: r.RequiredParameters,
+			AllowedParameters:  r.AllowedParameters,
+			DeniedParameters:   r.DeniedParameters,
+		}
+		if jr.Capabilities == nil {
+			jr.Capabilities = []Capability{}
+		}
+		if r.MinWrappingTTL > 0 {
+			jr.MinWrappingTTL = formatDuration(r.MinWrappingTTL)
+		}
+		if r.MaxWrappingTTL > 0 {
+			jr.MaxWrappingTTL = formatDuration(r.MaxWrappingTTL)
+		}
+		paths[r.Path] = jr
+	}
+	return json.MarshalIndent(map[string]interface{}{"path": paths}, "", "  ")
+}
+
+// mergeRules merges two rules of the same path the way Vault does: capabilities and parameters are combined,
+// an empty list of values wins over any other, and the lowest wrapping TTLs are kept. Neither rule is modified.
+func mergeRules(a, b PathRule) PathRule {
+	merged := a
+	merged.Capabilities = mergeCapabilities(append([]Capability{}, a.Capabilities...), b.Capabilities)
+	merged.RequiredParameters = append([]string{}, a.RequiredParameters...)
+	for _, name := range b.RequiredParameters {
+		if !containsString(merged.RequiredParameters, name) {
+			merged.RequiredParameters = append(merged.RequiredParameters, name)
+		}
+	}
+	if len(merged.RequiredParameters) == 0 {
+		merged.RequiredParameters = nil
+	}
+	merged.AllowedParameters = mergeParameters(a.AllowedParameters, b.AllowedParameters)
+	merged.DeniedParameters = mergeParameters(a.DeniedParameters, b.DeniedParameters)
+	if b.MinWrappingTTL > 0 && (merged.MinWrappingTTL == 0 || b.MinWrappingTTL < merged.MinWrappingTTL) {
+		merged.MinWrappingTTL = b.MinWrappingTTL
+	}
+	if b.MaxWrappingTTL > 0 && (merged.MaxWrappingTTL == 0 || b.MaxWrappingTTL < merged.MaxWrappingTTL) {
+		merged.MaxWrappingTTL = b.MaxWrappingTTL
+	}
+	return merged
+}
+
+func mergeParameters(a, b map[string][]interface{}) map[string][]interface{} {
+	if a == nil && b == nil {
+		return nil
+	}
+	merged := make(map[string][]interface{}, len(a)+len(b))
+	for name, values := range a {
+		merged[name] = append([]interface{}{}, values...)
+	}
+	for name, values := range b {
+		existing, ok := merged[name]
+		switch {
+		case !ok:
+			merged[name] = append([]interface{}{}, values...)
+		case len(existing) == 0 || len(values) == 0:
+			merged[name] = []interface{}{}
+		default:
+			merged[name] = append(existing, values...)
+		}
+	}
+	return merged
+}
+
+// Parse parses the policy text returned by CheckPolicy into a structured Policy
+func (d PolicyData) Parse() (Policy, error) {
+	return ParsePolicy(d.Name, d.Policy)
+}
+
+// ReadPolicy reads policy 'name' and parses it into a structured Policy
+func (v *Vault) ReadPolicy(ctx context.Context, name string) (p Policy, err error) {
+	d, err := v.CheckPolicy(ctx, name)
+	if err != nil {
+		return
+	}
+	return d.Parse()
+}
+
+// UpsertPolicyModel creates/updates the policy rendered as HCL, named after Policy.Name.
+// Together with ReadPolicy this allows read-modify-write of policies.
+func (v *Vault) UpsertPolicyModel(ctx context.Context, p Policy) (err error) {
+	if p.Name == "" {
+		return fmt.Errorf("policy name cannot be empty")
+	}
+	body := map[string]string{"policy": p.HCL()}
+	return v.doJSON(ctx, http.MethodPut, "/sys/policies/acl/"+p.Name, body, nil)
+}
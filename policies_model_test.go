@@ -0,0 +1,164 @@
+package forest
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPolicyHCL = `
+# Order service
+path "kv/ms-order-*" {
+  capabilities = ["read", "list"]
+}
+
+// Encrypt only
+path "transit/encrypt/pii" {
+  capabilities = ["update"]
+  required_parameters = ["plaintext"]
+  allowed_parameters = {
+    "plaintext" = []
+    "key_version" = [1, 2]
+  }
+  denied_parameters = {
+    "nonce" = []
+  }
+  min_wrapping_ttl = "1m"
+  max_wrapping_ttl = 3600
+}
+
+/* legacy syntax */
+path "secret/old" { policy = "write" }
+
+path "sys/*" {
+  capabilities = ["deny"]
+}
+`
+
+func Test_ParsePolicy_HCL(t *testing.T) {
+	p, err := ParsePolicy("ms-order", testPolicyHCL)
+	require.NoError(t, err)
+	assert.Equal(t, "ms-order", p.Name)
+	require.Len(t, p.Paths, 4)
+
+	assert.Equal(t, "kv/ms-order-*", p.Paths[0].Path)
+	assert.Equal(t, []Capability{CapabilityRead, CapabilityList}, p.Paths[0].Capabilities)
+
+	pii := p.Paths[1]
+	assert.Equal(t, []string{"plaintext"}, pii.RequiredParameters)
+	assert.Equal(t, map[string][]interface{}{"plaintext": {}, "key_version": {json.Number("1"), json.Number("2")}}, pii.AllowedParameters)
+	assert.Equal(t, map[string][]interface{}{"nonce": {}}, pii.DeniedParameters)
+	assert.Equal(t, time.Minute, pii.MinWrappingTTL)
+	assert.Equal(t, time.Hour, pii.MaxWrappingTTL)
+
+	assert.Equal(t, []Capability{CapabilityCreate, CapabilityRead, CapabilityUpdate, CapabilityDelete, CapabilityList}, p.Paths[2].Capabilities)
+	assert.Equal(t, []Capability{CapabilityDeny}, p.Paths[3].Capabilities)
+}
+
+func Test_ParsePolicy_RoundTrip(t *testing.T) {
+	p, err := ParsePolicy("ms-order", testPolicyHCL)
+	require.NoError(t, err)
+
+	again, err := ParsePolicy("ms-order", p.HCL())
+	require.NoError(t, err)
+	assert.Equal(t, p, again)
+
+	js, err := p.JSON()
+	require.NoError(t, err)
+	fromJSON, err := ParsePolicy("ms-order", string(js))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, p.Paths, fromJSON.Paths)
+}
+
+func Test_Policy_JSON_DuplicatePaths(t *testing.T) {
+	caps := make([]Capability, 1, 4)
+	caps[0] = CapabilityRead
+	p := Policy{Name: "dup", Paths: []PathRule{
+		{
+			Path:               "transit/encrypt/pii",
+			Capabilities:       caps,
+			RequiredParameters: []string{"plaintext"},
+			AllowedParameters:  map[string][]interface{}{"key_version": {json.Number("1")}, "context": {}},
+			MaxWrappingTTL:     time.Hour,
+		},
+		{
+			Path:               "transit/encrypt/pii",
+			Capabilities:       []Capability{CapabilityUpdate, CapabilityRead},
+			RequiredParameters: []string{"context"},
+			AllowedParameters:  map[string][]interface{}{"key_version": {json.Number("2")}, "context": {"abc"}, "plaintext": {}},
+			DeniedParameters:   map[string][]interface{}{"nonce": {}},
+			MinWrappingTTL:     time.Minute,
+			MaxWrappingTTL:     30 * time.Minute,
+		},
+	}}
+	js, err := p.JSON()
+	require.NoError(t, err)
+	// The caller's slices are left untouched
+	assert.Equal(t, []Capability{CapabilityRead, ""}, caps[:2])
+	assert.Equal(t, map[string][]interface{}{"key_version": {json.Number("1")}, "context": {}}, p.Paths[0].AllowedParameters)
+
+	merged, err := ParsePolicy("dup", string(js))
+	require.NoError(t, err)
+	require.Len(t, merged.Paths, 1)
+	r := merged.Paths[0]
+	assert.Equal(t, []Capability{CapabilityRead, CapabilityUpdate}, r.Capabilities)
+	assert.Equal(t, []string{"plaintext", "context"}, r.RequiredParameters)
+	assert.Equal(t, map[string][]interface{}{
+		"key_version": {json.Number("1"), json.Number("2")}, "context": {}, "plaintext": {},
+	}, r.AllowedParameters)
+	assert.Equal(t, map[string][]interface{}{"nonce": {}}, r.DeniedParameters)
+	assert.Equal(t, time.Minute, r.MinWrappingTTL)
+	assert.Equal(t, 30*time.Minute, r.MaxWrappingTTL)
+}
+
+func Test_ParsePolicy_NonASCIIComment(t *testing.T) {
+	p, err := ParsePolicy("x", "/* 日本語の\nコメント */ path \"kv/a\" { capabilities = [\"read\"] } # 注釈\npath \"kv/b\" {}")
+	require.NoError(t, err)
+	require.Len(t, p.Paths, 2)
+	assert.Equal(t, "kv/a", p.Paths[0].Path)
+	assert.Equal(t, []Capability{CapabilityRead}, p.Paths[0].Capabilities)
+	assert.Equal(t, "kv/b", p.Paths[1].Path)
+}
+
+func Test_ParsePolicy_Invalid(t *testing.T) {
+	for _, text := range []string{
+		`path "a" { capabilities = ["read"`,
+		`path "a" { capabilities = "read" }`,
+		`path "a" { unknown = true }`,
+		`path "a" { policy = "everything" }`,
+		`paths "a" {}`,
+		`path "a { }`,
+		`{"path": {"a": {"capabilities": ["read"]}`,
+		`/* 日本語 path "a" { capabilities = ["read"] }`,
+	} {
+		_, err := ParsePolicy("invalid", text)
+		assert.Error(t, err, text)
+	}
+}
+
+func Test_Policy_ReadModifyWrite(t *testing.T) {
+	p := Policy{
+		Name: "test-policy-model",
+		Paths: []PathRule{
+			{Path: "forest_kv/forest-data", Capabilities: []Capability{CapabilityRead}},
+		},
+	}
+	err := UpsertPolicyModel(context.TODO(), p)
+	require.NoError(t, err)
+
+	got, err := ReadPolicy(context.TODO(), p.Name)
+	require.NoError(t, err)
+	assert.Equal(t, p, got)
+
+	got.SetRule(PathRule{Path: "forest_transit_test/encrypt/aes", Capabilities: []Capability{CapabilityUpdate}})
+	err = UpsertPolicyModel(context.TODO(), got)
+	require.NoError(t, err)
+
+	again, err := ReadPolicy(context.TODO(), p.Name)
+	require.NoError(t, err)
+	assert.Len(t, again.Paths, 2)
+}
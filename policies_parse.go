@@ -0,0 +1,459 @@
+package forest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// hclObject keeps items in the order they are written, and allows repeated keys like multiple path blocks
+type hclObject []hclItem
+
+type hclItem struct {
+	Key   string
+	Value interface{} // string, json.Number, bool, []interface{} or hclObject
+}
+
+// ParsePolicy parses a policy written in either HCL or JSON syntax, like the text returned by CheckPolicy
+func ParsePolicy(name, text string) (p Policy, err error) {
+	var root hclObject
+	if strings.HasPrefix(strings.TrimSpace(text), "{") {
+		root, err = parseJSONObject(text)
+	} else {
+		root, err = parseHCL(text)
+	}
+	if err != nil {
+		return p, fmt.Errorf("fail to parse policy '%s': %w", name, err)
+	}
+	p.Name = name
+	for _, item := range root {
+		switch item.Key {
+		case "name":
+		case "path":
+			paths, ok := item.Value.(hclObject)
+			if !ok {
+				return p, fmt.Errorf("policy '%s': path must be an object", name)
+			}
+			for _, path := range paths {
+				attrs, ok := path.Value.(hclObject)
+				if !ok {
+					return p, fmt.Errorf("policy '%s': path '%s' must be an object", name, path.Key)
+				}
+				rule, err := parsePathRule(path.Key, attrs)
+				if err != nil {
+					return p, fmt.Errorf("policy '%s': path '%s': %w", name, path.Key, err)
+				}
+				p.Paths = append(p.Paths, rule)
+			}
+		default:
+			return p, fmt.Errorf("policy '%s': unsupported key '%s'", name, item.Key)
+		}
+	}
+	return p, nil
+}
+
+// legacyPolicies maps the deprecated 'policy' field to capabilities, the same way Vault does
+var legacyPolicies = map[string][]Capability{
+	"deny":  {CapabilityDeny},
+	"read":  {CapabilityRead, CapabilityList},
+	"write": {CapabilityCreate, CapabilityRead, CapabilityUpdate, CapabilityDelete, CapabilityList},
+	"sudo":  {CapabilityCreate, CapabilityRead, CapabilityUpdate, CapabilityDelete, CapabilityList, CapabilitySudo},
+}
+
+func parsePathRule(path string, attrs hclObject) (r PathRule, err error) {
+	r.Path = path
+	for _, attr := range attrs {
+		switch attr.Key {
+		case "capabilities":
+			list, err := stringList(attr.Value)
+			if err != nil {
+				return r, fmt.Errorf("capabilities: %w", err)
+			}
+			for _, c := range list {
+				r.Capabilities = appendCapability(r.Capabilities, Capability(strings.ToLower(c)))
+			}
+		case "policy":
+			s, _ := attr.Value.(string)
+			caps, ok := legacyPolicies[strings.ToLower(s)]
+			if !ok {
+				return r, fmt.Errorf("invalid policy '%v'", attr.Value)
+			}
+			for _, c := range caps {
+				r.Capabilities = appendCapability(r.Capabilities, c)
+			}
+		case "required_parameters":
+			if r.RequiredParameters, err = stringList(attr.Value); err != nil {
+				return r, fmt.Errorf("required_parameters: %w", err)
+			}
+		case "allowed_parameters":
+			if r.AllowedParameters, err = parameterMap(attr.Value); err != nil {
+				return r, fmt.Errorf("allowed_parameters: %w", err)
+			}
+		case "denied_parameters":
+			if r.DeniedParameters, err = parameterMap(attr.Value); err != nil {
+				return r, fmt.Errorf("denied_parameters: %w", err)
+			}
+		case "min_wrapping_ttl":
+			if r.MinWrappingTTL, err = parseTTL(attr.Value); err != nil {
+				return r, fmt.Errorf("min_wrapping_ttl: %w", err)
+			}
+		case "max_wrapping_ttl":
+			if r.MaxWrappingTTL, err = parseTTL(attr.Value); err != nil {
+				return r, fmt.Errorf("max_wrapping_ttl: %w", err)
+			}
+		default:
+			return r, fmt.Errorf("unsupported key '%s'", attr.Key)
+		}
+	}
+	return r, nil
+}
+
+func appendCapability(caps []Capability, c Capability) []Capability {
+	for _, existing := range caps {
+		if existing == c {
+			return caps
+		}
+	}
+	return append(caps, c)
+}
+
+func stringList(v interface{}) ([]string, error) {
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, errors.New("must be a list")
+	}
+	out := make([]string, 0, len(list))
+	for _, item := range list {
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("'%v' is not a string", item)
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+func parameterMap(v interface{}) (map[string][]interface{}, error) {
+	obj, ok := v.(hclObject)
+	if !ok {
+		return nil, errors.New("must be an object")
+	}
+	params := make(map[string][]interface{}, len(obj))
+	for _, item := range obj {
+		list, ok := item.Value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("'%s' must be a list", item.Key)
+		}
+		if existing, ok := params[item.Key]; ok {
+			list = append(existing, list...)
+		}
+		params[item.Key] = list
+	}
+	return params, nil
+}
+
+// parseTTL accepts a duration string like "1h", or a number of seconds either as number or string
+func parseTTL(v interface{}) (time.Duration, error) {
+	var s string
+	switch t := v.(type) {
+	case string:
+		s = t
+	case json.Number:
+		s = t.String()
+	default:
+		return 0, fmt.Errorf("invalid duration '%v'", v)
+	}
+	if secs, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Duration(secs) * time.Second, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// parseJSONObject decodes a JSON object into hclObject, keeping the key order
+func parseJSONObject(text string) (hclObject, error) {
+	dec := json.NewDecoder(strings.NewReader(text))
+	dec.UseNumber()
+	v, err := decodeJSONValue(dec)
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := v.(hclObject)
+	if !ok {
+		return nil, errors.New("policy must be a JSON object")
+	}
+	return obj, nil
+}
+
+func decodeJSONValue(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			obj := hclObject{}
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				value, err := decodeJSONValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				obj = append(obj, hclItem{Key: key.(string), Value: value})
+			}
+			_, err = dec.Token()
+			return obj, err
+		case '[':
+			list := []interface{}{}
+			for dec.More() {
+				value, err := decodeJSONValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, value)
+			}
+			_, err = dec.Token()
+			return list, err
+		}
+		return nil, fmt.Errorf("unexpected '%v'", t)
+	default:
+		return t, nil
+	}
+}
+
+// hclParser parses the subset of HCL used by Vault policies: blocks, attributes, strings, numbers,
+// booleans, lists, objects and comments.
+type hclParser struct {
+	src  []rune
+	pos  int
+	line int
+}
+
+type hclToken struct {
+	kind  rune // One of { } [ ] = : , or 's' string, 'i' identifier, 'n' number, 0 end of input
+	value string
+}
+
+func parseHCL(text string) (hclObject, error) {
+	p := &hclParser{src: []rune(text), line: 1}
+	obj, err := p.parseBody(false)
+	if err != nil {
+		return nil, fmt.Errorf("line %d: %w", p.line, err)
+	}
+	return obj, nil
+}
+
+// parseBody parses items until '}' if nested, or end of input otherwise
+func (p *hclParser) parseBody(nested bool) (hclObject, error) {
+	obj := hclObject{}
+	for {
+		tok, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case tok.kind == 0 && !nested, tok.kind == '}' && nested:
+			return obj, nil
+		case tok.kind == ',':
+			continue
+		case tok.kind != 's' && tok.kind != 'i':
+			return nil, fmt.Errorf("unexpected %s, expecting key", tok)
+		}
+		keys := []string{tok.value}
+		var value interface{}
+	keys:
+		for {
+			tok, err = p.next()
+			if err != nil {
+				return nil, err
+			}
+			switch tok.kind {
+			case 's', 'i':
+				keys = append(keys, tok.value)
+			case '=', ':':
+				if value, err = p.parseValue(); err != nil {
+					return nil, err
+				}
+				break keys
+			case '{':
+				if value, err = p.parseBody(true); err != nil {
+					return nil, err
+				}
+				break keys
+			default:
+				return nil, fmt.Errorf("unexpected %s after key '%s'", tok, keys[len(keys)-1])
+			}
+		}
+		// path "a" { ... } is the same as path = { "a" = { ... } }
+		for i := len(keys) - 1; i > 0; i-- {
+			value = hclObject{{Key: keys[i], Value: value}}
+		}
+		obj = append(obj, hclItem{Key: keys[0], Value: value})
+	}
+}
+
+func (p *hclParser) parseValue() (interface{}, error) {
+	tok, err := p.next()
+	if err != nil {
+		return nil, err
+	}
+	switch tok.kind {
+	case 's':
+		return tok.value, nil
+	case 'n':
+		return json.Number(tok.value), nil
+	case 'i':
+		switch tok.value {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		return nil, fmt.Errorf("unexpected identifier '%s', expecting value", tok.value)
+	case '{':
+		return p.parseBody(true)
+	case '[':
+		list := []interface{}{}
+		for {
+			pos, line := p.pos, p.line
+			tok, err := p.next()
+			if err != nil {
+				return nil, err
+			}
+			if tok.kind == ']' {
+				return list, nil
+			}
+			p.pos, p.line = pos, line
+			value, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+			tok, err = p.next()
+			if err != nil {
+				return nil, err
+			}
+			switch tok.kind {
+			case ',':
+			case ']':
+				return list, nil
+			default:
+				return nil, fmt.Errorf("unexpected %s in list", tok)
+			}
+		}
+	}
+	return nil, fmt.Errorf("unexpected %s, expecting value", tok)
+}
+
+func (t hclToken) String() string {
+	switch t.kind {
+	case 0:
+		return "end of input"
+	case 's':
+		return strconv.Quote(t.value)
+	case 'i', 'n':
+		return "'" + t.value + "'"
+	}
+	return "'" + string(t.kind) + "'"
+}
+
+func (p *hclParser) next() (tok hclToken, err error) {
+	if err = p.skipSpaceAndComments(); err != nil {
+		return
+	}
+	if p.pos >= len(p.src) {
+		return hclToken{}, nil
+	}
+	c := p.src[p.pos]
+	switch {
+	case strings.ContainsRune("{}[]=:,", c):
+		p.pos++
+		return hclToken{kind: c}, nil
+	case c == '"':
+		return p.readString()
+	case c == '-' || unicode.IsDigit(c):
+		start := p.pos
+		p.pos++
+		for p.pos < len(p.src) && strings.ContainsRune("0123456789.eE+-", p.src[p.pos]) {
+			p.pos++
+		}
+		return hclToken{kind: 'n', value: string(p.src[start:p.pos])}, nil
+	case unicode.IsLetter(c) || c == '_':
+		start := p.pos
+		for p.pos < len(p.src) && (unicode.IsLetter(p.src[p.pos]) || unicode.IsDigit(p.src[p.pos]) || strings.ContainsRune("_-.", p.src[p.pos])) {
+			p.pos++
+		}
+		return hclToken{kind: 'i', value: string(p.src[start:p.pos])}, nil
+	}
+	return tok, fmt.Errorf("unexpected character '%c'", c)
+}
+
+func (p *hclParser) readString() (tok hclToken, err error) {
+	start := p.pos
+	p.pos++
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case '\\':
+			p.pos += 2
+			continue
+		case '\n':
+			return tok, errors.New("unterminated string")
+		case '"':
+			p.pos++
+			s, err := strconv.Unquote(string(p.src[start:p.pos]))
+			if err != nil {
+				return tok, fmt.Errorf("invalid string %s", string(p.src[start:p.pos]))
+			}
+			return hclToken{kind: 's', value: s}, nil
+		}
+		p.pos++
+	}
+	return tok, errors.New("unterminated string")
+}
+
+func (p *hclParser) skipSpaceAndComments() error {
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '\n':
+			p.line++
+			p.pos++
+		case unicode.IsSpace(c):
+			p.pos++
+		case c == '#' || (c == '/' && p.peek(1) == '/'):
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		case c == '/' && p.peek(1) == '*':
+			p.pos += 2
+			for p.pos < len(p.src) && !(p.src[p.pos] == '*' && p.peek(1) == '/') {
+				if p.src[p.pos] == '\n' {
+					p.line++
+				}
+				p.pos++
+			}
+			if p.pos >= len(p.src) {
+				return errors.New("unterminated comment")
+			}
+			p.pos += 2
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func (p *hclParser) peek(offset int) rune {
+	if p.pos+offset < len(p.src) {
+		return p.src[p.pos+offset]
+	}
+	return 0
+}
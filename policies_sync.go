@@ -0,0 +1,260 @@
+package forest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// ListPolicies returns names of every ACL policy, including the built-in 'root' and 'default'
+func (v *Vault) ListPolicies(ctx context.Context) (names []string, err error) {
+	req, err := v.requestGen(ctx, http.MethodGet, "/sys/policies/acl?list=true", nil)
+	if err != nil {
+		return
+	}
+	res, err := v.send(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+	var ddd configList
+	if err = json.NewDecoder(res.Body).Decode(&ddd); err != nil {
+		return nil, err
+	}
+	return ddd.Data.Keys, nil
+}
+
+// DeletePolicy deletes policy 'name'. Tokens already attached to it lose its permissions immediately.
+// Deleting a policy which does not exist is not an error.
+func (v *Vault) DeletePolicy(ctx context.Context, name string) (err error) {
+	if name == "" {
+		return fmt.Errorf("policy name cannot be empty")
+	}
+	if isBuiltinPolicy(name) {
+		return fmt.Errorf("policy '%s' cannot be deleted", name)
+	}
+	return v.doJSON(ctx, http.MethodDelete, "/sys/policies/acl/"+name, nil, nil)
+}
+
+func isBuiltinPolicy(name string) bool {
+	return name == "root" || name == "default"
+}
+
+// PolicyAction is the change SyncPolicies makes to a single policy
+type PolicyAction string
+
+const (
+	// PolicyCreate means the policy does not exist in Vault yet
+	PolicyCreate PolicyAction = "create"
+	// PolicyUpdate means the policy exists in Vault with different rules
+	PolicyUpdate PolicyAction = "update"
+	// PolicyDelete means the policy exists in Vault but not in the desired set
+	PolicyDelete PolicyAction = "delete"
+)
+
+// PolicyChange is a single planned change of SyncPolicies
+type PolicyChange struct {
+	Name   string
+	Action PolicyAction
+	Diff   string // Line diff of the current and desired HCL, lines prefixed by "+ ", "- " or "  "
+}
+
+// PolicyPlan is the list of changes needed to make Vault match the desired policies, ordered by action then name
+type PolicyPlan struct {
+	Changes []PolicyChange
+}
+
+// Empty reports whether Vault already matches the desired policies
+func (p PolicyPlan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// String renders the plan as a human readable diff
+func (p PolicyPlan) String() string {
+	if p.Empty() {
+		return "No policy changes.\n"
+	}
+	var sb strings.Builder
+	counts := make(map[PolicyAction]int)
+	for _, c := range p.Changes {
+		counts[c.Action]++
+		fmt.Fprintf(&sb, "%s policy %q\n", c.Action, c.Name)
+		sb.WriteString(c.Diff)
+		sb.WriteString("\n")
+	}
+	fmt.Fprintf(&sb, "Plan: %d to create, %d to update, %d to delete.\n",
+		counts[PolicyCreate], counts[PolicyUpdate], counts[PolicyDelete])
+	return sb.String()
+}
+
+// SyncOptions configures SyncPolicies
+type SyncOptions struct {
+	// DryRun only computes the plan, nothing is written to Vault
+	DryRun bool
+	// Prefix limits the sync to policies whose name starts with it. Policies outside of the prefix are never touched,
+	// and desired policies outside of it are rejected. Required unless AllowDeleteUnprefixed is set.
+	Prefix string
+	// AllowDeleteUnprefixed allows an empty Prefix, which manages every policy in Vault:
+	// any policy not in the desired set is deleted, including ones owned by other teams.
+	AllowDeleteUnprefixed bool
+	// Output receives the rendered plan. Optional, the plan is returned either way.
+	Output io.Writer
+}
+
+// SyncPolicies makes the ACL policies in Vault match 'desired', keyed by policy name.
+// Policies missing in Vault are created, differing ones are updated and those not in 'desired' are deleted,
+// within SyncOptions.Prefix. The built-in 'root' and 'default' policies are never touched.
+//
+// Policies are compared by their normalized HCL, so formatting and comments of the existing policy don't matter.
+// The computed plan is returned even on error, so the caller can tell what has not been applied.
+//
+// Example: `plan, err := vault.SyncPolicies(ctx, policies, forest.SyncOptions{Prefix: "ms-order-", DryRun: true})`
+func (v *Vault) SyncPolicies(ctx context.Context, desired map[string]Policy, opts SyncOptions) (plan PolicyPlan, err error) {
+	if opts.Prefix == "" && !opts.AllowDeleteUnprefixed {
+		return plan, errors.New("sync without prefix deletes every policy not in the desired set, set AllowDeleteUnprefixed to allow it")
+	}
+	names := make([]string, 0, len(desired))
+	for name := range desired {
+		if name == "" {
+			return plan, fmt.Errorf("policy name cannot be empty")
+		}
+		if !strings.HasPrefix(name, opts.Prefix) {
+			return plan, fmt.Errorf("policy '%s' is outside of prefix '%s'", name, opts.Prefix)
+		}
+		if isBuiltinPolicy(name) {
+			return plan, fmt.Errorf("policy '%s' cannot be managed by sync", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	existing, err := v.ListPolicies(ctx)
+	if err != nil {
+		return
+	}
+	current := make(map[string]bool, len(existing))
+	for _, name := range existing {
+		if strings.HasPrefix(name, opts.Prefix) && !isBuiltinPolicy(name) {
+			current[name] = true
+		}
+	}
+
+	var creates, updates, deletes []PolicyChange
+	for _, name := range names {
+		want := desired[name].HCL()
+		if !current[name] {
+			creates = append(creates, PolicyChange{Name: name, Action: PolicyCreate, Diff: lineDiff("", want)})
+			continue
+		}
+		have, err := v.currentPolicyHCL(ctx, name)
+		if err != nil {
+			return plan, err
+		}
+		if have != want {
+			updates = append(updates, PolicyChange{Name: name, Action: PolicyUpdate, Diff: lineDiff(have, want)})
+		}
+	}
+	for _, name := range existing {
+		if !current[name] {
+			continue
+		}
+		if _, ok := desired[name]; ok {
+			continue
+		}
+		have, err := v.currentPolicyHCL(ctx, name)
+		if err != nil {
+			return plan, err
+		}
+		deletes = append(deletes, PolicyChange{Name: name, Action: PolicyDelete, Diff: lineDiff(have, "")})
+	}
+	sort.Slice(deletes, func(i, j int) bool { return deletes[i].Name < deletes[j].Name })
+	plan.Changes = append(append(append(plan.Changes, creates...), updates...), deletes...)
+
+	if opts.Output != nil {
+		if _, err = io.WriteString(opts.Output, plan.String()); err != nil {
+			return
+		}
+	}
+	if opts.DryRun {
+		return
+	}
+
+	for _, c := range plan.Changes {
+		switch c.Action {
+		case PolicyCreate, PolicyUpdate:
+			p := desired[c.Name]
+			p.Name = c.Name
+			err = v.UpsertPolicyModel(ctx, p)
+		case PolicyDelete:
+			err = v.DeletePolicy(ctx, c.Name)
+		}
+		if err != nil {
+			return plan, fmt.Errorf("fail to %s policy '%s': %w", c.Action, c.Name, err)
+		}
+	}
+	return
+}
+
+// currentPolicyHCL returns the normalized HCL of policy 'name', or its raw text if it can't be parsed
+func (v *Vault) currentPolicyHCL(ctx context.Context, name string) (string, error) {
+	d, err := v.CheckPolicy(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	p, err := d.Parse()
+	if err != nil {
+		return d.Policy, nil
+	}
+	return p.HCL(), nil
+}
+
+// lineDiff returns a minimal line diff turning a into b, based on the longest common subsequence
+func lineDiff(a, b string) string {
+	x, y := splitLines(a), splitLines(b)
+	// lcs[i][j] is the length of the longest common subsequence of x[i:] and y[j:]
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	var sb strings.Builder
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			sb.WriteString("  " + x[i] + "\n")
+			i++
+			j++
+		case i < len(x) && (j == len(y) || lcs[i+1][j] >= lcs[i][j+1]):
+			sb.WriteString("- " + x[i] + "\n")
+			i++
+		default:
+			sb.WriteString("+ " + y[j] + "\n")
+			j++
+		}
+	}
+	return sb.String()
+}
+
+func splitLines(s string) []string {
+	s = strings.TrimSuffix(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
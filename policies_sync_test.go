@@ -0,0 +1,96 @@
+package forest
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LineDiff(t *testing.T) {
+	assert.Equal(t, "", lineDiff("", ""))
+	assert.Equal(t, "+ a\n+ b\n", lineDiff("", "a\nb\n"))
+	assert.Equal(t, "- a\n- b\n", lineDiff("a\nb\n", ""))
+	assert.Equal(t, "  a\n- b\n+ x\n  c\n", lineDiff("a\nb\nc\n", "a\nx\nc\n"))
+	assert.Equal(t, "  a\n+ b\n  c\n", lineDiff("a\nc", "a\nb\nc"))
+}
+
+func Test_SyncPolicies(t *testing.T) {
+	ctx := context.TODO()
+	const prefix = "test-sync-"
+	read := Policy{Paths: []PathRule{{Path: "forest_kv/forest-data", Capabilities: []Capability{CapabilityRead}}}}
+	write := Policy{Paths: []PathRule{{Path: "forest_kv/forest-data", Capabilities: []Capability{CapabilityUpdate}}}}
+
+	// Start from a known state, and make sure nothing outside of the prefix is touched
+	_, err := SyncPolicies(ctx, map[string]Policy{}, SyncOptions{Prefix: prefix})
+	require.NoError(t, err)
+	err = UpsertPolicyModel(ctx, Policy{Name: "test-outside-sync", Paths: read.Paths})
+	require.NoError(t, err)
+
+	t.Run("Create", func(t *testing.T) {
+		var out bytes.Buffer
+		plan, err := SyncPolicies(ctx, map[string]Policy{prefix + "a": read, prefix + "b": read}, SyncOptions{Prefix: prefix, Output: &out})
+		require.NoError(t, err)
+		require.Len(t, plan.Changes, 2)
+		assert.Equal(t, PolicyCreate, plan.Changes[0].Action)
+		assert.Equal(t, prefix+"a", plan.Changes[0].Name)
+		assert.Contains(t, out.String(), "Plan: 2 to create, 0 to update, 0 to delete.")
+
+		names, err := ListPolicies(ctx)
+		require.NoError(t, err)
+		assert.Subset(t, names, []string{prefix + "a", prefix + "b", "test-outside-sync"})
+	})
+
+	t.Run("Dry Run", func(t *testing.T) {
+		var out bytes.Buffer
+		desired := map[string]Policy{prefix + "a": write}
+		plan, err := SyncPolicies(ctx, desired, SyncOptions{Prefix: prefix, DryRun: true, Output: &out})
+		require.NoError(t, err)
+		require.Len(t, plan.Changes, 2)
+		assert.Equal(t, PolicyChange{Name: prefix + "a", Action: PolicyUpdate, Diff: lineDiff(read.HCL(), write.HCL())}, plan.Changes[0])
+		assert.Equal(t, PolicyDelete, plan.Changes[1].Action)
+		assert.Contains(t, out.String(), `-   capabilities = ["read"]`)
+		assert.Contains(t, out.String(), `+   capabilities = ["update"]`)
+
+		got, err := ReadPolicy(ctx, prefix+"a")
+		require.NoError(t, err)
+		assert.Equal(t, read.Paths, got.Paths)
+	})
+
+	t.Run("Update And Delete", func(t *testing.T) {
+		desired := map[string]Policy{prefix + "a": write}
+		_, err := SyncPolicies(ctx, desired, SyncOptions{Prefix: prefix})
+		require.NoError(t, err)
+
+		plan, err := SyncPolicies(ctx, desired, SyncOptions{Prefix: prefix})
+		require.NoError(t, err)
+		assert.True(t, plan.Empty())
+
+		names, err := ListPolicies(ctx)
+		require.NoError(t, err)
+		assert.NotContains(t, names, prefix+"b")
+		assert.Contains(t, names, "test-outside-sync")
+		assert.Contains(t, names, "default")
+	})
+
+	t.Run("Outside Prefix", func(t *testing.T) {
+		_, err := SyncPolicies(ctx, map[string]Policy{"other": read}, SyncOptions{Prefix: prefix})
+		assert.Error(t, err)
+	})
+
+	require.NoError(t, DeletePolicy(ctx, "test-outside-sync"))
+	_, err = SyncPolicies(ctx, map[string]Policy{}, SyncOptions{Prefix: prefix})
+	require.NoError(t, err)
+}
+
+func Test_SyncPolicies_RequiresPrefix(t *testing.T) {
+	// Checked before anything is read from Vault
+	v, err := NewClient("x", WithHost("http://127.0.0.1:1"))
+	require.NoError(t, err)
+	_, err = v.SyncPolicies(context.TODO(), map[string]Policy{"ms-order": {}}, SyncOptions{DryRun: true})
+	assert.Error(t, err)
+	_, err = v.SyncPolicies(context.TODO(), map[string]Policy{}, SyncOptions{})
+	assert.Error(t, err)
+}
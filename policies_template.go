@@ -0,0 +1,128 @@
+package forest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// Vault identity templating placeholders, resolved by Vault for each request.
+// They can be used as is in policy templates, see PolicyTemplate.
+const (
+	IdentityEntityID   = "{{identity.entity.id}}"
+	IdentityEntityName = "{{identity.entity.name}}"
+)
+
+// identityPlaceholder matches Vault identity templating placeholders, which are not valid Go template actions
+var identityPlaceholder = regexp.MustCompile(`\{\{\s*(identity\.[^{}]*?)\s*\}\}`)
+
+// PolicyTemplate renders near-identical policies, like one per service, from a Go text/template.
+// Both the name and the text are templates executed with the same data, and the text may be HCL or JSON.
+//
+// Vault identity placeholders such as {{identity.entity.name}} are kept as is in the rendered policy,
+// so they are resolved by Vault at request time instead of when rendering.
+//
+// Example:
+//
+//	t, err := forest.NewPolicyTemplate("{{.Service}}-svc", `
+//	path "kv/{{.Service}}-*" { capabilities = ["read"] }
+//	path "transit/encrypt/{{.Service}}" { capabilities = ["update"] }
+//	path "kv/users/{{identity.entity.name}}/*" { capabilities = ["read"] }
+//	`)
+//	policy, err := t.Render(map[string]string{"Service": "ms-order"})
+type PolicyTemplate struct {
+	name *template.Template
+	text *template.Template
+}
+
+// NewPolicyTemplate parses the name and text templates
+func NewPolicyTemplate(name, text string) (*PolicyTemplate, error) {
+	nt, err := parsePolicyTemplate("name", name)
+	if err != nil {
+		return nil, err
+	}
+	tt, err := parsePolicyTemplate("policy", text)
+	if err != nil {
+		return nil, err
+	}
+	return &PolicyTemplate{name: nt, text: tt}, nil
+}
+
+// MustPolicyTemplate is like NewPolicyTemplate but panics on error. Meant for package level templates.
+func MustPolicyTemplate(name, text string) *PolicyTemplate {
+	t, err := NewPolicyTemplate(name, text)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func parsePolicyTemplate(name, text string) (*template.Template, error) {
+	// Turn identity placeholders into actions printing themselves
+	text = identityPlaceholder.ReplaceAllStringFunc(text, func(s string) string {
+		inner := identityPlaceholder.FindStringSubmatch(s)[1]
+		return fmt.Sprintf("{{%q}}", "{{"+inner+"}}")
+	})
+	t, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid policy template: %w", err)
+	}
+	return t, nil
+}
+
+// Render executes the templates with 'data' and parses the result into a Policy
+func (t *PolicyTemplate) Render(data interface{}) (p Policy, err error) {
+	var name, text strings.Builder
+	if err = t.name.Execute(&name, data); err != nil {
+		return p, fmt.Errorf("fail to render policy name: %w", err)
+	}
+	if name.Len() == 0 {
+		return p, errors.New("policy name cannot be empty")
+	}
+	if err = t.text.Execute(&text, data); err != nil {
+		return p, fmt.Errorf("fail to render policy '%s': %w", name.String(), err)
+	}
+	return ParsePolicy(name.String(), text.String())
+}
+
+// UpsertPolicyTemplate renders the template with 'data', and creates/updates the resulting policy
+func (v *Vault) UpsertPolicyTemplate(ctx context.Context, t *PolicyTemplate, data interface{}) (p Policy, err error) {
+	if p, err = t.Render(data); err != nil {
+		return
+	}
+	err = v.UpsertPolicyModel(ctx, p)
+	return
+}
+
+// ApplyPolicyTemplateToken renders and upserts the policy, then creates a token with it using 'token'.
+// The policy is added to the policies already set on 'token'. A nil 'token' creates one with default options.
+//
+// Example: `p, token, err := vault.ApplyPolicyTemplateToken(ctx, t, svc, vault.CreateNewToken().WithPeriod(24 * time.Hour))`
+func (v *Vault) ApplyPolicyTemplateToken(ctx context.Context, t *PolicyTemplate, data interface{}, token *CreateTokenInstance) (p Policy, clientToken string, err error) {
+	if p, err = v.UpsertPolicyTemplate(ctx, t, data); err != nil {
+		return
+	}
+	if token == nil {
+		token = v.CreateNewToken()
+	}
+	token.Policies = append(token.Policies, p.Name)
+	clientToken, err = token.Do(ctx)
+	return
+}
+
+// ApplyPolicyTemplateRole renders and upserts the policy, then creates/updates token role 'role' allowing it.
+// The policy is added to role.AllowedPolicies, and the role is named after the policy if role.Name is empty.
+func (v *Vault) ApplyPolicyTemplateRole(ctx context.Context, t *PolicyTemplate, data interface{}, role TokenRole) (p Policy, err error) {
+	if p, err = v.UpsertPolicyTemplate(ctx, t, data); err != nil {
+		return
+	}
+	if role.Name == "" {
+		role.Name = p.Name
+	}
+	role.AllowedPolicies = append(append([]string(nil), role.AllowedPolicies...), p.Name)
+	err = v.UpsertTokenRole(ctx, role)
+	return
+}
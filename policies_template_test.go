@@ -0,0 +1,83 @@
+package forest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testServiceTemplate = `
+path "forest_kv/{{.Service}}-*" {
+  capabilities = ["read"]
+}
+
+path "forest_transit_test/encrypt/{{.Key}}" {
+  capabilities = ["update"]
+}
+
+path "forest_kv/users/{{ identity.entity.name }}/*" {
+  capabilities = ["read", "list"]
+}
+`
+
+func Test_PolicyTemplate_Render(t *testing.T) {
+	tmpl, err := NewPolicyTemplate("test-{{.Service}}-svc", testServiceTemplate)
+	require.NoError(t, err)
+
+	p, err := tmpl.Render(map[string]string{"Service": "ms-order", "Key": "aes"})
+	require.NoError(t, err)
+	assert.Equal(t, "test-ms-order-svc", p.Name)
+	require.Len(t, p.Paths, 3)
+	assert.Equal(t, "forest_kv/ms-order-*", p.Paths[0].Path)
+	assert.Equal(t, "forest_transit_test/encrypt/aes", p.Paths[1].Path)
+	assert.Equal(t, "forest_kv/users/"+IdentityEntityName+"/*", p.Paths[2].Path)
+
+	_, err = tmpl.Render(map[string]string{"Service": "ms-order"})
+	assert.Error(t, err, "missing key")
+
+	_, err = NewPolicyTemplate("name", `path "{{.Broken" {}`)
+	assert.Error(t, err)
+
+	empty := MustPolicyTemplate("{{.Name}}", testServiceTemplate)
+	_, err = empty.Render(map[string]string{"Name": ""})
+	assert.Error(t, err)
+}
+
+func Test_PolicyTemplate_Apply(t *testing.T) {
+	ctx := context.TODO()
+	tmpl := MustPolicyTemplate("test-{{.Service}}-svc", testServiceTemplate)
+	data := map[string]string{"Service": "ms-order", "Key": "aes"}
+
+	t.Run("Token", func(t *testing.T) {
+		p, token, err := ApplyPolicyTemplateToken(ctx, tmpl, data, CreateNewToken().WithTimeToLive(10*time.Minute))
+		require.NoError(t, err)
+		defer RevokeToken(ctx, token)
+		look, err := LookupOther(ctx, token)
+		require.NoError(t, err)
+		assert.Contains(t, look.Data.Policies, p.Name)
+
+		caps, err := Capabilities(ctx, token, "forest_kv/ms-order-conf", "forest_kv/ms-payment-conf")
+		require.NoError(t, err)
+		assert.True(t, caps.Can("forest_kv/ms-order-conf", CapabilityRead))
+		assert.False(t, caps.Can("forest_kv/ms-payment-conf", CapabilityRead))
+	})
+
+	t.Run("Role", func(t *testing.T) {
+		p, err := ApplyPolicyTemplateRole(ctx, tmpl, data, TokenRole{TokenTTL: 10 * time.Minute})
+		require.NoError(t, err)
+		defer DeleteTokenRole(ctx, p.Name)
+		roles, err := ListTokenRoles(ctx)
+		require.NoError(t, err)
+		assert.Contains(t, roles, p.Name)
+
+		token, err := CreateNewToken().WithRole(p.Name).Do(ctx)
+		require.NoError(t, err)
+		defer RevokeToken(ctx, token)
+		look, err := LookupOther(ctx, token)
+		require.NoError(t, err)
+		assert.Contains(t, look.Data.Policies, p.Name)
+	})
+}
@@ -1,52 +1,366 @@
 package forest
 
 import (
-	"bytes"
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
 )
 
-type streamInBetween struct {
-	startData  bool
-	initiate   strings.Builder
-	temp       *bytes.Buffer
-	startPoint string
-	endPoint   rune
+// jsonStringStream returns the decoded content of the string found at path in the JSON object read from src.
+// src is read incrementally, so memory usage does not grow with the size of the value. Escape sequences are decoded,
+// fields may come in any order, and an error is returned if the value never appears.
+type jsonStringStream struct {
+	src     *bufio.Reader
+	closer  io.Closer
+	path    []string
+	started bool
+	done    bool
+	pending []byte
+	scratch [utf8.UTFMax]byte
 }
 
-func newStreamInBetween(startPoint string, endPoint rune) *streamInBetween {
-	return &streamInBetween{
-		temp:       bytes.NewBuffer(nil),
-		startPoint: startPoint,
-		endPoint:   endPoint,
+func newJSONStringStream(src io.ReadCloser, path ...string) *jsonStringStream {
+	return &jsonStringStream{
+		src:    bufio.NewReader(src),
+		closer: src,
+		path:   path,
 	}
 }
 
-func (e *streamInBetween) Read(p []byte) (n int, err error) {
-	return e.temp.Read(p)
+func (j *jsonStringStream) Read(p []byte) (n int, err error) {
+	if j.done {
+		return 0, io.EOF
+	}
+	if !j.started {
+		found, err := j.findField(j.path)
+		if err != nil {
+			return 0, j.finish(err)
+		}
+		if !found {
+			return 0, j.finish(fmt.Errorf("field '%s' not found in response", strings.Join(j.path, ".")))
+		}
+		j.started = true
+	}
+	for n < len(p) {
+		if len(j.pending) > 0 {
+			c := copy(p[n:], j.pending)
+			j.pending = j.pending[c:]
+			n += c
+			continue
+		}
+		if n > 0 && j.src.Buffered() == 0 {
+			break
+		}
+		b, err := j.src.ReadByte()
+		if err != nil {
+			return n, j.finish(unexpectedEOF(err))
+		}
+		switch {
+		case b == '"':
+			return n, j.finish(io.EOF)
+		case b == '\\':
+			if j.pending, err = j.readEscape(); err != nil {
+				return n, j.finish(err)
+			}
+		case b < 0x20:
+			return n, j.finish(errors.New("invalid control character in JSON string"))
+		default:
+			p[n] = b
+			n++
+		}
+	}
+	return n, nil
 }
 
-func (e *streamInBetween) Write(p []byte) (n int, err error) {
-	for _, b := range p {
-		if e.startData {
-			// Stop early since we hit the closing quote
-			if b == byte(e.endPoint) {
-				return 0, nil
+func (j *jsonStringStream) finish(err error) error {
+	j.done = true
+	j.closer.Close()
+	return err
+}
+
+// findField consumes the object at the current position until the string value at path is reached.
+// found is false when the object has been fully consumed without finding it.
+func (j *jsonStringStream) findField(path []string) (found bool, err error) {
+	if err = j.expect('{'); err != nil {
+		return
+	}
+	b, err := j.nextNonSpace()
+	if err != nil || b == '}' {
+		return false, err
+	}
+	j.src.UnreadByte()
+	for {
+		if err = j.expect('"'); err != nil {
+			return
+		}
+		key, err := j.readString()
+		if err != nil {
+			return false, err
+		}
+		if err = j.expect(':'); err != nil {
+			return false, err
+		}
+		b, err := j.nextNonSpace()
+		if err != nil {
+			return false, err
+		}
+		switch {
+		case key == path[0] && len(path) == 1 && b == '"':
+			return true, nil
+		case key == path[0] && len(path) > 1 && b == '{':
+			j.src.UnreadByte()
+			if found, err = j.findField(path[1:]); found || err != nil {
+				return found, err
+			}
+		default:
+			j.src.UnreadByte()
+			if err = j.skipValue(); err != nil {
+				return false, err
 			}
-			err = e.temp.WriteByte(b)
+		}
+		b, err = j.nextNonSpace()
+		if err != nil {
+			return false, err
+		}
+		switch b {
+		case ',':
+		case '}':
+			return false, nil
+		default:
+			return false, fmt.Errorf("invalid character '%c' after object value", b)
+		}
+	}
+}
+
+func (j *jsonStringStream) skipValue() error {
+	b, err := j.nextNonSpace()
+	if err != nil {
+		return err
+	}
+	switch b {
+	case '"':
+		return j.skipString()
+	case '{', '[':
+		depth := 1
+		for depth > 0 {
+			b, err := j.src.ReadByte()
 			if err != nil {
-				return 0, err
+				return unexpectedEOF(err)
+			}
+			switch b {
+			case '"':
+				if err = j.skipString(); err != nil {
+					return err
+				}
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
 			}
-		} else {
-			err = e.initiate.WriteByte(b)
+		}
+		return nil
+	default:
+		// number, true, false or null
+		for {
+			b, err := j.src.ReadByte()
 			if err != nil {
-				return 0, err
+				return unexpectedEOF(err)
 			}
-			dd := e.initiate.String()
-			if strings.Contains(dd, e.startPoint) {
-				e.startData = true
+			if b == ',' || b == '}' || b == ']' || isSpace(b) {
+				return j.src.UnreadByte()
 			}
 		}
-		n++
 	}
+}
+
+// skipString consumes the rest of a string whose opening quote has been read, without keeping its content
+func (j *jsonStringStream) skipString() error {
+	for {
+		b, err := j.src.ReadByte()
+		if err != nil {
+			return unexpectedEOF(err)
+		}
+		switch b {
+		case '"':
+			return nil
+		case '\\':
+			if _, err = j.src.ReadByte(); err != nil {
+				return unexpectedEOF(err)
+			}
+		}
+	}
+}
+
+// readString reads the rest of a string whose opening quote has been read. Only used for object keys.
+func (j *jsonStringStream) readString() (string, error) {
+	var sb strings.Builder
+	for {
+		b, err := j.src.ReadByte()
+		if err != nil {
+			return "", unexpectedEOF(err)
+		}
+		switch b {
+		case '"':
+			return sb.String(), nil
+		case '\\':
+			decoded, err := j.readEscape()
+			if err != nil {
+				return "", err
+			}
+			sb.Write(decoded)
+		default:
+			sb.WriteByte(b)
+		}
+	}
+}
+
+// readEscape decodes an escape sequence whose backslash has been read
+func (j *jsonStringStream) readEscape() ([]byte, error) {
+	b, err := j.src.ReadByte()
+	if err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	switch b {
+	case '"', '\\', '/':
+		j.scratch[0] = b
+	case 'b':
+		j.scratch[0] = '\b'
+	case 'f':
+		j.scratch[0] = '\f'
+	case 'n':
+		j.scratch[0] = '\n'
+	case 'r':
+		j.scratch[0] = '\r'
+	case 't':
+		j.scratch[0] = '\t'
+	case 'u':
+		r, err := j.readHex()
+		if err != nil {
+			return nil, err
+		}
+		if utf16.IsSurrogate(r) {
+			r2 := unicode.ReplacementChar
+			if next, _ := j.src.Peek(2); string(next) == `\u` {
+				j.src.Discard(2)
+				if r2, err = j.readHex(); err != nil {
+					return nil, err
+				}
+			}
+			r = utf16.DecodeRune(r, r2)
+		}
+		return j.scratch[:utf8.EncodeRune(j.scratch[:], r)], nil
+	default:
+		return nil, fmt.Errorf("invalid escape character '%c' in JSON string", b)
+	}
+	return j.scratch[:1], nil
+}
+
+func (j *jsonStringStream) readHex() (rune, error) {
+	var hex [4]byte
+	if _, err := io.ReadFull(j.src, hex[:]); err != nil {
+		return 0, unexpectedEOF(err)
+	}
+	r, err := strconv.ParseUint(string(hex[:]), 16, 16)
+	if err != nil {
+		return 0, fmt.Errorf("invalid unicode escape '\\u%s' in JSON string", hex[:])
+	}
+	return rune(r), nil
+}
+
+func (j *jsonStringStream) expect(c byte) error {
+	b, err := j.nextNonSpace()
+	if err != nil {
+		return err
+	}
+	if b != c {
+		return fmt.Errorf("invalid character '%c' in response, expecting '%c'", b, c)
+	}
+	return nil
+}
+
+func (j *jsonStringStream) nextNonSpace() (byte, error) {
+	for {
+		b, err := j.src.ReadByte()
+		if err != nil {
+			return 0, unexpectedEOF(err)
+		}
+		if !isSpace(b) {
+			return b, nil
+		}
+	}
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
+
+// unexpectedEOF converts EOF into ErrUnexpectedEOF, since a JSON value ending early is never a clean end of stream
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// streamJSONString writes `{"field":"` + payload + `"}` to w. If encode is true, payload is base64 encoded on the fly,
+// otherwise it must be a transit ciphertext, which is written as is after checking its charset.
+func streamJSONString(w io.Writer, field string, payload io.Reader, encode bool) (err error) {
+	if _, err = io.WriteString(w, `{"`+field+`":"`); err != nil {
+		return
+	}
+	if encode {
+		encoder := base64.NewEncoder(base64.StdEncoding, w)
+		if _, err = io.Copy(encoder, payload); err != nil {
+			return
+		}
+		// Close has to be called now to flush the remaining partial block
+		if err = encoder.Close(); err != nil {
+			return
+		}
+	} else if _, err = io.Copy(ciphertextWriter{w}, payload); err != nil {
+		return
+	}
+	_, err = io.WriteString(w, `"}`)
 	return
 }
+
+// ciphertextWriter fails on any byte outside of the vault:vN:base64 charset,
+// so a ciphertext can't break out of the JSON string it's written into
+type ciphertextWriter struct {
+	w io.Writer
+}
+
+func (c ciphertextWriter) Write(p []byte) (int, error) {
+	for _, b := range p {
+		if !isCiphertextByte(b) {
+			return 0, fmt.Errorf("%w: invalid character %q", ErrNotCiphertext, b)
+		}
+	}
+	return c.w.Write(p)
+}
+
+func isCiphertextByte(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9' || b == '+' || b == '/' || b == '=' || b == ':'
+}
+
+// pipeJSONString returns a reader streaming `{"field":"` + payload + `"}` as it's being read
+func pipeJSONString(field string, payload io.Reader, encode bool) *io.PipeReader {
+	pr, pw := io.Pipe()
+	go func() {
+		// Buffered so the request is sent in large chunks rather than one per base64 block
+		w := bufio.NewWriterSize(pw, 32<<10)
+		err := streamJSONString(w, field, payload, encode)
+		if err == nil {
+			err = w.Flush()
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr
+}
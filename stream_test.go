@@ -3,7 +3,14 @@ package forest
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
 	"strings"
 	"testing"
 
@@ -34,4 +41,169 @@ func Test_Stream(t *testing.T) {
 
 		assert.Equal(t, want, string(got))
 	})
+
+	t.Run("Testing Decrypt Stream Error Response", func(t *testing.T) {
+		_, err := TransitDecryptStream(context.TODO(), "aes", strings.NewReader("vault:v1:bm90LWEtY2lwaGVy"))
+		require.Error(t, err)
+	})
+}
+
+// drainingTransit fakes transit encrypt and decrypt without buffering anything: the request is drained,
+// and a ciphertext or base64 plaintext about as long as the request body is streamed back
+func drainingTransit(t testing.TB) (*Vault, *httptest.Server) {
+	stream := func(field string, pad int64) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			n, _ := io.Copy(ioutil.Discard, r.Body)
+			io.WriteString(w, `{"data":{"`+field+`":"`)
+			io.Copy(w, io.LimitReader(repeatReader('A'), n-n%pad))
+			io.WriteString(w, `"}}`)
+		}
+	}
+	return fakeVault(t, map[string]http.HandlerFunc{
+		"/forest_transit_test/encrypt/aes": stream("ciphertext", 1),
+		"/forest_transit_test/decrypt/aes": stream("plaintext", 4),
+	}, WithTransitEngine("forest_transit_test"))
+}
+
+// repeatReader reads the same byte endlessly
+type repeatReader byte
+
+func (r repeatReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = byte(r)
+	}
+	return len(p), nil
+}
+
+func encryptDiscard(tb testing.TB, v *Vault, size int64) {
+	data, err := v.TransitEncryptStream(context.TODO(), "aes", io.LimitReader(rand.Reader, size))
+	require.NoError(tb, err)
+	n, err := io.Copy(ioutil.Discard, data)
+	require.NoError(tb, err)
+	require.True(tb, n > size, "ciphertext of %d bytes for a %d bytes payload", n, size)
+}
+
+func decryptDiscard(tb testing.TB, v *Vault, size int64) {
+	cipher := io.MultiReader(strings.NewReader("vault:v1:"), io.LimitReader(repeatReader('A'), size))
+	data, err := v.TransitDecryptStream(context.TODO(), "aes", cipher)
+	require.NoError(tb, err)
+	n, err := io.Copy(ioutil.Discard, data)
+	require.NoError(tb, err)
+	require.True(tb, n >= size*3/4, "plaintext of %d bytes for a %d bytes ciphertext", n, size)
+}
+
+// allocated returns the bytes allocated by one call of stream with a payload of 'size' bytes
+func allocated(tb testing.TB, v *Vault, stream func(testing.TB, *Vault, int64), size int64) int64 {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	stream(tb, v, size)
+	runtime.ReadMemStats(&after)
+	return int64(after.TotalAlloc - before.TotalAlloc)
+}
+
+// Test_TransitEncryptStream_Memory shows memory is bounded: allocated bytes stay flat while payload size grows,
+// since neither the request nor the response is buffered whole
+func Test_TransitEncryptStream_Memory(t *testing.T) {
+	v, server := drainingTransit(t)
+	defer server.Close()
+	allocated(t, v, encryptDiscard, 1<<10) // Open the connection
+	small, large := allocated(t, v, encryptDiscard, 1<<20), allocated(t, v, encryptDiscard, 16<<20)
+	assert.Less(t, large, small+1<<20, "16MB payload allocated %d bytes, 1MB payload %d bytes", large, small)
+}
+
+// Test_TransitDecryptStream_Memory is the same for decrypt, whose plaintext is read out of the response by jsonStringStream
+func Test_TransitDecryptStream_Memory(t *testing.T) {
+	v, server := drainingTransit(t)
+	defer server.Close()
+	allocated(t, v, decryptDiscard, 1<<10) // Open the connection
+	small, large := allocated(t, v, decryptDiscard, 1<<20), allocated(t, v, decryptDiscard, 16<<20)
+	assert.Less(t, large, small+1<<20, "16MB payload allocated %d bytes, 1MB payload %d bytes", large, small)
+}
+
+// Benchmark_TransitEncryptStream reports allocations per payload size, which stay flat as it grows
+func Benchmark_TransitEncryptStream(b *testing.B) {
+	benchmarkStream(b, encryptDiscard)
+}
+
+// Benchmark_TransitDecryptStream reports allocations per payload size, which stay flat as it grows
+func Benchmark_TransitDecryptStream(b *testing.B) {
+	benchmarkStream(b, decryptDiscard)
+}
+
+func benchmarkStream(b *testing.B, stream func(testing.TB, *Vault, int64)) {
+	v, server := drainingTransit(b)
+	defer server.Close()
+	for _, size := range []int64{1 << 20, 4 << 20, 16 << 20} {
+		b.Run(fmt.Sprintf("%dMB", size>>20), func(b *testing.B) {
+			b.SetBytes(size)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				stream(b, v, size)
+			}
+		})
+	}
+}
+
+func Test_StreamJSONString_Ciphertext(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, streamJSONString(&buf, "ciphertext", strings.NewReader("vault:v1:ab+/cd=="), false))
+	assert.Equal(t, `{"ciphertext":"vault:v1:ab+/cd=="}`, buf.String())
+
+	buf.Reset()
+	err := streamJSONString(&buf, "ciphertext", strings.NewReader(`vault:v1:abc","context":"eHl6`), false)
+	assert.True(t, errors.Is(err, ErrNotCiphertext))
+	assert.NotContains(t, buf.String(), "context")
+}
+
+func Test_StreamJSONString(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Plain",
+			body: `{"request_id":"abc","data":{"ciphertext":"vault:v1:abc="}}`,
+			want: "vault:v1:abc=",
+		},
+		{
+			name: "Reordered And Nested Decoys",
+			body: `{"warnings":["\"ciphertext\":\"nope"],"ciphertext":"top","data":{"key_version":1,"meta":{"ciphertext":"deep"},"ciphertext" : "vault:v1:xyz"}}`,
+			want: "vault:v1:xyz",
+		},
+		{
+			name: "Escaped Characters",
+			body: `{"data":{"ciphertext":"a\"b\\c\/d\né😀"}}`,
+			want: "a\"b\\c/d\né😀",
+		},
+		{
+			name:    "Missing Field",
+			body:    `{"data":{"key_version":1},"warnings":null}`,
+			wantErr: true,
+		},
+		{
+			name:    "Null Data",
+			body:    `{"data":null}`,
+			wantErr: true,
+		},
+		{
+			name:    "Truncated",
+			body:    `{"data":{"ciphertext":"vault:v1:ab`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newJSONStringStream(ioutil.NopCloser(strings.NewReader(tt.body)), "data", "ciphertext")
+			got, err := ioutil.ReadAll(s)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
 }
@@ -0,0 +1,114 @@
+package forest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrInvalidAccessor is wrapped by LookupAccessor errors when Vault rejects the accessor,
+// which happens once its token has expired or been revoked
+var ErrInvalidAccessor = errors.New("invalid accessor")
+
+type sAccessor struct {
+	Accessor string `json:"accessor"`
+}
+
+// ListAccessors lists accessors of all tokens. Token used in the instance must have sudo on auth/token/accessors.
+func (v *Vault) ListAccessors(ctx context.Context) (accessors []string, err error) {
+	req, err := v.requestGen(ctx, http.MethodGet, "/auth/token/accessors?list=true", nil)
+	if err != nil {
+		return
+	}
+	res, err := v.Config.HTTPClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+	if err := checkErrorResponse(res); err != nil {
+		return nil, err
+	}
+	var ddd configList
+	err = json.NewDecoder(res.Body).Decode(&ddd)
+	if err != nil {
+		return nil, err
+	}
+	return ddd.Data.Keys, nil
+}
+
+// LookupAccessor lookup information on the token of given accessor. The token ID is not returned.
+// The error wraps ErrInvalidAccessor if the token no longer exists.
+func (v *Vault) LookupAccessor(ctx context.Context, accessor string) (lookup LookupToken, err error) {
+	b, err := json.Marshal(sAccessor{Accessor: accessor})
+	if err != nil {
+		return
+	}
+	req, err := v.requestGen(ctx, http.MethodPost, "/auth/token/lookup-accessor", bytes.NewBuffer(b))
+	if err != nil {
+		return
+	}
+	res, err := v.Config.HTTPClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+	if err := checkErrorResponse(res); err != nil {
+		if res.StatusCode == http.StatusBadRequest {
+			return lookup, fmt.Errorf("%w: %s", ErrInvalidAccessor, err)
+		}
+		return lookup, err
+	}
+	err = json.NewDecoder(res.Body).Decode(&lookup)
+	return
+}
+
+// LookupAllAccessors walks every accessor and returns the lookup result of each token.
+// Tokens that expire or get revoked while walking are skipped.
+func (v *Vault) LookupAllAccessors(ctx context.Context) (lookups []LookupToken, err error) {
+	accessors, err := v.ListAccessors(ctx)
+	if err != nil {
+		return
+	}
+	lookups = make([]LookupToken, 0, len(accessors))
+	for _, accessor := range accessors {
+		lookup, err := v.LookupAccessor(ctx, accessor)
+		if err != nil {
+			if errors.Is(err, ErrInvalidAccessor) {
+				continue
+			}
+			return nil, err
+		}
+		lookups = append(lookups, lookup)
+	}
+	return lookups, nil
+}
+
+// RenewTokenAccessor attempts to renew the token of given accessor using self's token
+func (v *Vault) RenewTokenAccessor(ctx context.Context, accessor string) (err error) {
+	_, err = v.RenewTokenAccessorIncrement(ctx, accessor, 0)
+	return
+}
+
+// RevokeAccessor revokes the token of given accessor and all of its children
+func (v *Vault) RevokeAccessor(ctx context.Context, accessor string) (err error) {
+	b, err := json.Marshal(sAccessor{Accessor: accessor})
+	if err != nil {
+		return
+	}
+	req, err := v.requestGen(ctx, http.MethodPost, "/auth/token/revoke-accessor", bytes.NewBuffer(b))
+	if err != nil {
+		return
+	}
+	res, err := v.Config.HTTPClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+	if err := checkErrorResponse(res); err != nil {
+		return err
+	}
+	return
+}
@@ -0,0 +1,98 @@
+package forest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TokenAccessor(t *testing.T) {
+	token, err := CreateNewToken().WithPolicies("default").WithDisplayName("accessor").WithTimeToLive(1 * time.Hour).Do(context.TODO())
+	require.NoError(t, err)
+	look, err := LookupOther(context.TODO(), token)
+	require.NoError(t, err)
+	accessor := look.Data.Accessor
+
+	t.Run("List Accessors", func(t *testing.T) {
+		accessors, err := ListAccessors(context.TODO())
+		require.NoError(t, err)
+		assert.Contains(t, accessors, accessor)
+	})
+
+	t.Run("Lookup Accessor", func(t *testing.T) {
+		look, err := LookupAccessor(context.TODO(), accessor)
+		require.NoError(t, err)
+		assert.Equal(t, accessor, look.Data.Accessor)
+		assert.Empty(t, look.Data.ID)
+		assert.Equal(t, "token-accessor", look.Data.DisplayName)
+	})
+
+	t.Run("Lookup All Accessors", func(t *testing.T) {
+		lookups, err := LookupAllAccessors(context.TODO())
+		require.NoError(t, err)
+		var found bool
+		for _, l := range lookups {
+			found = found || l.Data.Accessor == accessor
+		}
+		assert.True(t, found)
+	})
+
+	t.Run("Renew Accessor", func(t *testing.T) {
+		err := RenewTokenAccessor(context.TODO(), accessor)
+		require.NoError(t, err)
+	})
+
+	t.Run("Revoke Accessor", func(t *testing.T) {
+		err := RevokeAccessor(context.TODO(), accessor)
+		require.NoError(t, err)
+		_, err = LookupOther(context.TODO(), token)
+		assert.Error(t, err)
+		_, err = LookupAccessor(context.TODO(), accessor)
+		assert.True(t, errors.Is(err, ErrInvalidAccessor), "%v", err)
+	})
+}
+
+func Test_LookupAllAccessors_Skip(t *testing.T) {
+	// Token of accessor 'gone' is revoked between listing and lookup, 'denied' can't be looked up at all
+	denied := false
+	v, server := fakeVault(t, map[string]http.HandlerFunc{
+		"/auth/token/accessors": func(w http.ResponseWriter, r *http.Request) {
+			if denied {
+				w.Write([]byte(`{"data":{"keys":["alive","gone","denied"]}}`))
+				return
+			}
+			w.Write([]byte(`{"data":{"keys":["alive","gone"]}}`))
+		},
+		"/auth/token/lookup-accessor": func(w http.ResponseWriter, r *http.Request) {
+			var body sAccessor
+			json.NewDecoder(r.Body).Decode(&body)
+			switch body.Accessor {
+			case "gone":
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"errors":["invalid accessor"]}`))
+			case "denied":
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"errors":["permission denied"]}`))
+			default:
+				w.Write([]byte(`{"data":{"accessor":"` + body.Accessor + `"}}`))
+			}
+		},
+	})
+	defer server.Close()
+
+	lookups, err := v.LookupAllAccessors(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, lookups, 1)
+	assert.Equal(t, "alive", lookups[0].Data.Accessor)
+
+	denied = true
+	_, err = v.LookupAllAccessors(context.TODO())
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrInvalidAccessor))
+}
@@ -1,12 +1,13 @@
 package forest
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"io/ioutil"
+	"errors"
+	"fmt"
+	"net"
 	"net/http"
-	"strconv"
+	"net/url"
+	"strings"
 	"time"
 )
 
@@ -18,7 +19,7 @@ type CreateTokenInstance struct {
 	Meta            map[string]string `json:"meta,omitempty"`
 	NoParent        bool              `json:"no_parent,omitempty"`
 	NoDefaultPolicy bool              `json:"no_default_policy,omitempty"`
-	Renewable       bool              `json:"renewable,omitempty"`
+	Renewable       bool              `json:"renewable"`
 	TTL             string            `json:"ttl,omitempty"`
 	Type            string            `json:"type,omitempty"`
 	ExplicitMaxTTL  string            `json:"explicit_max_ttl,omitempty"`
@@ -26,9 +27,30 @@ type CreateTokenInstance struct {
 	NumUses         int               `json:"num_uses,omitempty"`
 	EntityAlias     string            `json:"entity_alias,omitempty"`
 	Period          string            `json:"period,omitempty"`
-	authToken       string
-	baseURL         string
-	httpClient      *http.Client
+	BoundCIDRs      []string          `json:"bound_cidrs,omitempty"`
+	orphan          bool
+	role            string
+	vault           *Vault
+	err             error
+}
+
+// TokenAuth is the auth block returned when a token is created or renewed
+type TokenAuth struct {
+	ClientToken   string            `json:"client_token"`
+	Accessor      string            `json:"accessor"`
+	Policies      []string          `json:"policies"`
+	TokenPolicies []string          `json:"token_policies"`
+	Metadata      map[string]string `json:"metadata"`
+	LeaseDuration int64             `json:"lease_duration"` // Seconds. Use LeaseTTL()
+	Renewable     bool              `json:"renewable"`
+	EntityID      string            `json:"entity_id"`
+	TokenType     string            `json:"token_type"`
+	Orphan        bool              `json:"orphan"`
+}
+
+// LeaseTTL returns the lease duration granted by Vault
+func (a TokenAuth) LeaseTTL() time.Duration {
+	return time.Duration(a.LeaseDuration) * time.Second
 }
 
 type tokenResponse struct {
@@ -39,18 +61,7 @@ type tokenResponse struct {
 	Data          interface{} `json:"data"`
 	WrapInfo      interface{} `json:"wrap_info"`
 	Warnings      []string    `json:"warnings"`
-	Auth          struct {
-		ClientToken   string            `json:"client_token"`
-		Accessor      string            `json:"accessor"`
-		Policies      []string          `json:"policies"`
-		TokenPolicies []string          `json:"token_policies"`
-		Metadata      map[string]string `json:"metadata"`
-		LeaseDuration int64             `json:"lease_duration"`
-		Renewable     bool              `json:"renewable"`
-		EntityID      string            `json:"entity_id"`
-		TokenType     string            `json:"token_type"`
-		Orphan        bool              `json:"orphan"`
-	} `json:"auth"`
+	Auth          TokenAuth   `json:"auth"`
 }
 
 // CreateNewToken creates an instance of Token override. Call ``.Do(ctx)`` on the instance to actually create new token.
@@ -62,9 +73,7 @@ func (v *Vault) CreateNewToken() *CreateTokenInstance {
 	return &CreateTokenInstance{
 		Renewable:   true,
 		DisplayName: "token",
-		authToken:   v.Config.Token,
-		baseURL:     v.BaseURL,
-		httpClient:  v.Config.HTTPClient,
+		vault:       v,
 	}
 }
 
@@ -74,7 +83,8 @@ func (c *CreateTokenInstance) WithID(id string) *CreateTokenInstance {
 	return c
 }
 
-// WithRoleName replaces instance rolename.
+// WithRoleName replaces instance rolename. It's only sent as the role_name field to auth/token/create,
+// the token does not follow the role's settings. Use WithRole to create the token against a token role instead.
 func (c *CreateTokenInstance) WithRoleName(rolename string) *CreateTokenInstance {
 	c.RoleName = rolename
 	return c
@@ -94,6 +104,9 @@ func (c *CreateTokenInstance) WithMeta(metadata map[string]string) *CreateTokenI
 
 // WithCurrentTokenAsParent Uses instance token as parent. Default true.
 // If using DoOverride(token), the override token will be set as parent instead.
+//
+// Setting this to false through auth/token/create requires sudo. Use WithOrphan if the token used
+// only has update permission on auth/token/create-orphan.
 func (c *CreateTokenInstance) WithCurrentTokenAsParent(b bool) *CreateTokenInstance {
 	c.NoParent = !b
 	return c
@@ -111,31 +124,18 @@ func (c *CreateTokenInstance) WithRenewableStatus(b bool) *CreateTokenInstance {
 	return c
 }
 
-// WithTimeToLive replaces instance time to live, which by default will depends on Vault's default lease TTL
-// If this method is called, duration will be rounded down to the nearest hour argument passed with minimum value of 1 hour
-// Only hourly is supported in this package.
+// WithTimeToLive replaces instance time to live, which by default will depends on Vault's default lease TTL.
+// Any positive duration is accepted, like 90 * time.Second. Vault works in whole seconds, so sub second durations are rounded up.
 func (c *CreateTokenInstance) WithTimeToLive(t time.Duration) *CreateTokenInstance {
-	h := t / time.Hour
-	if h == 0 {
-		c.TTL = "1h"
-	} else {
-		str := strconv.FormatInt(h.Nanoseconds(), 10)
-		c.TTL = str + "h"
-	}
+	c.TTL = c.duration("time to live", t)
 	return c
 }
 
 // WithPeriod replaces token period. Token that is not renewed in this set of time cannot be renewed again.
 // By default, if unset will follow's Vault's default lease TTL.
-// Has minimum value of 1 hour. Only hourly is supported in this package.
+// Any positive duration is accepted. Vault works in whole seconds, so sub second durations are rounded up.
 func (c *CreateTokenInstance) WithPeriod(t time.Duration) *CreateTokenInstance {
-	h := t / time.Hour
-	if h == 0 {
-		c.Period = "1h"
-	} else {
-		str := strconv.FormatInt(h.Nanoseconds(), 10)
-		c.Period = str + "h"
-	}
+	c.Period = c.duration("period", t)
 	return c
 }
 
@@ -151,16 +151,74 @@ func (c *CreateTokenInstance) WithSetAsServiceToken() *CreateTokenInstance {
 	return c
 }
 
-// WithExplicitMaxTTL replaces instance explicit time to live, which by default will depends on Vault's default lease TTL
-// If this method is called, duration will be rounded down to the nearest hour argument passed with minimum value of 1 hour
+// WithExplicitMaxTTL replaces instance explicit time to live, which by default will depends on Vault's default lease TTL.
+// Any positive duration is accepted. Vault works in whole seconds, so sub second durations are rounded up.
 func (c *CreateTokenInstance) WithExplicitMaxTTL(t time.Duration) *CreateTokenInstance {
-	h := t / time.Hour
-	if h == 0 {
-		c.ExplicitMaxTTL = "1h"
-	} else {
-		str := strconv.FormatInt(h.Nanoseconds(), 10)
-		c.ExplicitMaxTTL = str + "h"
+	c.ExplicitMaxTTL = c.duration("explicit max TTL", t)
+	return c
+}
+
+// duration formats t in Vault's duration syntax. Non positive duration is recorded as error returned by Do.
+func (c *CreateTokenInstance) duration(name string, t time.Duration) string {
+	if t <= 0 {
+		if c.err == nil {
+			c.err = fmt.Errorf("%s must be positive, got %s", name, t)
+		}
+		return ""
+	}
+	return formatDuration(t)
+}
+
+// validate checks for combinations Vault would reject, before sending the request
+func (c *CreateTokenInstance) validate() error {
+	if c.err != nil {
+		return c.err
+	}
+	if c.NumUses < 0 {
+		return errors.New("number of uses cannot be negative")
+	}
+	if c.EntityAlias != "" && c.RoleName == "" && c.role == "" {
+		return errors.New("entity alias requires a role name")
+	}
+	if c.orphan && c.role != "" {
+		return errors.New("orphan endpoint cannot be used with a role. Set orphan in the token role instead")
+	}
+	if c.Type == "batch" {
+		switch {
+		case c.Period != "":
+			return errors.New("batch tokens cannot be periodic")
+		case c.ExplicitMaxTTL != "":
+			return errors.New("batch tokens cannot have explicit max TTL")
+		case c.NumUses > 0:
+			return errors.New("batch tokens cannot have limited number of uses")
+		}
+	}
+	return nil
+}
+
+// WithBoundCIDRs restricts the token to be used only from given CIDR blocks or IP addresses, like "10.0.0.0/8"
+func (c *CreateTokenInstance) WithBoundCIDRs(cidrs ...string) *CreateTokenInstance {
+	for _, cidr := range cidrs {
+		_, _, err := net.ParseCIDR(cidr)
+		if err != nil && net.ParseIP(cidr) == nil && c.err == nil {
+			c.err = fmt.Errorf("invalid bound CIDR: '%s'", cidr)
+		}
 	}
+	c.BoundCIDRs = cidrs
+	return c
+}
+
+// WithOrphan creates the token without parent through auth/token/create-orphan,
+// which only requires update permission on that path instead of sudo.
+func (c *CreateTokenInstance) WithOrphan() *CreateTokenInstance {
+	c.orphan = true
+	return c
+}
+
+// WithRole creates the token through auth/token/create/:role, so the token follows the token role's settings.
+// The role must exist within vault. Unlike WithRoleName, which only sets the role_name field, this changes the endpoint.
+func (c *CreateTokenInstance) WithRole(role string) *CreateTokenInstance {
+	c.role = role
 	return c
 }
 
@@ -176,72 +234,51 @@ func (c *CreateTokenInstance) WithNumberOfUses(i int) *CreateTokenInstance {
 	return c
 }
 
-// WithEntityAlias replaces instance entity alias. MUST BE USED alongside WithRoleName and Role name must exist within vault.
+// WithEntityAlias replaces instance entity alias. MUST BE USED alongside WithRole or WithRoleName and the role must exist within vault.
 func (c *CreateTokenInstance) WithEntityAlias(s string) *CreateTokenInstance {
 	c.EntityAlias = s
 	return c
 }
 
-// Do creates the token.
-func (c *CreateTokenInstance) Do(ctx context.Context) (token string, err error) {
-	body, err := json.Marshal(c)
-	if err != nil {
-		return "", err
-	}
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/auth/token/create", bytes.NewBuffer(body))
-	if err != nil {
-		return "", err
+// path returns the endpoint to create the token from
+func (c *CreateTokenInstance) path() string {
+	switch {
+	case c.orphan:
+		return "/auth/token/create-orphan"
+	case c.role != "":
+		return "/auth/token/create/" + url.PathEscape(c.role)
 	}
-	req.Header.Set("X-Vault-Token", c.authToken)
-	req.Header.Set("X-Vault-Request", "true")
-	req.Header.Set("Content-Type", "application/json")
-	res, err := c.httpClient.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer res.Body.Close()
-	if err := checkErrorResponse(res); err != nil {
-		return token, err
+	return "/auth/token/create"
+}
+
+// explain adds hints to permission errors when creating orphan tokens
+func (c *CreateTokenInstance) explain(err error) error {
+	msg := err.Error()
+	switch {
+	case c.orphan && strings.Contains(msg, "permission denied"):
+		return fmt.Errorf("%w. Creating orphan token requires update permission on auth/token/create-orphan", err)
+	case c.NoParent && !c.orphan && (strings.Contains(msg, "sudo") || strings.Contains(msg, "permission denied")):
+		return fmt.Errorf("%w. Creating orphan token through auth/token/create requires sudo, use WithOrphan() to create it through auth/token/create-orphan instead", err)
 	}
-	response := tokenResponse{}
-	responseBody, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return "", err
+	return err
+}
+
+// Do creates the token. Invalid combinations of options are returned as error before the request is sent.
+//
+// The instance token is used as auth, unless the context carries a token from ContextWithToken.
+func (c *CreateTokenInstance) Do(ctx context.Context) (token string, err error) {
+	if err = c.validate(); err != nil {
+		return
 	}
-	err = json.Unmarshal(responseBody, &response)
+	var response tokenResponse
+	err = c.vault.doJSON(ctx, http.MethodPost, c.path(), c, &response)
 	if err != nil {
-		return "", err
+		return "", c.explain(err)
 	}
-	token = response.Auth.ClientToken
-	return
+	return response.Auth.ClientToken, nil
 }
 
 // DoOverride creates the token, with passed token as auth and parent if orphan status is false (or no parent is true)
 func (c *CreateTokenInstance) DoOverride(ctx context.Context, authToken string) (token string, err error) {
-	body, err := json.Marshal(c)
-	if err != nil {
-		return "", err
-	}
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/auth/token/create", bytes.NewBuffer(body))
-	if err != nil {
-		return "", err
-	}
-	req.Header.Set("X-Vault-Token", authToken)
-	req.Header.Set("X-Vault-Request", "true")
-	req.Header.Set("Content-Type", "application/json")
-	res, err := c.httpClient.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer res.Body.Close()
-	if err := checkErrorResponse(res); err != nil {
-		return token, err
-	}
-	response := tokenResponse{}
-	err = json.NewDecoder(res.Body).Decode(&response)
-	if err != nil {
-		return "", err
-	}
-	token = response.Auth.ClientToken
-	return
+	return c.Do(ContextWithToken(ctx, authToken))
 }
@@ -66,3 +66,110 @@ func Test_CreateToken(t *testing.T) {
 		require.NotNil(t, err)
 	})
 }
+
+func Test_CreateToken_ShortTTL(t *testing.T) {
+	token, err := CreateNewToken().
+		WithPolicies("default").
+		WithTimeToLive(90 * time.Second).
+		WithExplicitMaxTTL(10 * time.Minute).
+		Do(context.Background())
+	require.NoError(t, err)
+
+	look, err := LookupOther(context.TODO(), token)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, look.Data.TTL, int64(90))
+	assert.Equal(t, 90*time.Second, look.CreationTTLDuration())
+	assert.Equal(t, 10*time.Minute, look.ExplicitMaxTTLDuration())
+}
+
+func Test_CreateToken_NotRenewable(t *testing.T) {
+	token, err := CreateNewToken().
+		WithPolicies("default").
+		WithTimeToLive(10 * time.Minute).
+		WithRenewableStatus(false).
+		Do(context.Background())
+	require.NoError(t, err)
+	defer RevokeToken(context.TODO(), token)
+
+	look, err := LookupOther(context.TODO(), token)
+	require.NoError(t, err)
+	assert.False(t, look.Data.Renewable)
+	assert.Error(t, RenewTokenOther(context.TODO(), token))
+
+	renewable, err := CreateNewToken().WithPolicies("default").WithTimeToLive(10 * time.Minute).Do(context.Background())
+	require.NoError(t, err)
+	defer RevokeToken(context.TODO(), renewable)
+
+	look, err = LookupOther(context.TODO(), renewable)
+	require.NoError(t, err)
+	assert.True(t, look.Data.Renewable)
+}
+
+func Test_CreateToken_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		instance *CreateTokenInstance
+	}{
+		{"Zero TTL", CreateNewToken().WithTimeToLive(0)},
+		{"Negative Period", CreateNewToken().WithPeriod(-time.Minute)},
+		{"Batch Periodic", CreateNewToken().WithSetAsBatchToken().WithPeriod(time.Hour)},
+		{"Batch Explicit Max TTL", CreateNewToken().WithSetAsBatchToken().WithExplicitMaxTTL(time.Hour)},
+		{"Batch Number Of Uses", CreateNewToken().WithSetAsBatchToken().WithNumberOfUses(3)},
+		{"Entity Alias Without Role", CreateNewToken().WithEntityAlias("alias")},
+		{"Invalid Bound CIDR", CreateNewToken().WithBoundCIDRs("10.0.0.0/8", "not-a-cidr")},
+		{"Orphan With Role", CreateNewToken().WithOrphan().WithRole("kunyit")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.instance.Do(context.TODO())
+			assert.Error(t, err)
+			_, err = tt.instance.DoOverride(context.TODO(), "s.token")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func Test_CreateToken_ValidateRole(t *testing.T) {
+	// Entity aliases are only accepted through auth/token/create/:role, which either setter covers
+	assert.NoError(t, CreateNewToken().WithRole("kunyit").WithEntityAlias("alias").validate())
+	assert.NoError(t, CreateNewToken().WithRoleName("kunyit").WithEntityAlias("alias").validate())
+}
+
+func Test_CreateToken_OrphanAndBoundCIDRs(t *testing.T) {
+	token, err := CreateNewToken().
+		WithPolicies("default").
+		WithTimeToLive(10 * time.Minute).
+		WithBoundCIDRs("127.0.0.1", "10.0.0.0/8").
+		WithOrphan().
+		Do(context.Background())
+	require.NoError(t, err)
+
+	look, err := LookupOther(context.TODO(), token)
+	require.NoError(t, err)
+	assert.True(t, look.Data.Orphan)
+	assert.ElementsMatch(t, []string{"127.0.0.1", "10.0.0.0/8"}, look.Data.BoundCIDRs)
+}
+
+func Test_CreateToken_Uninitialized(t *testing.T) {
+	saved := gVault
+	gVault = nil
+	defer func() { gVault = saved }()
+
+	_, err := CreateNewToken().WithPolicies("default").WithTimeToLive(time.Hour).Do(context.TODO())
+	assert.Error(t, err)
+}
+
+func Test_CreateToken_ContextToken(t *testing.T) {
+	parent, err := CreateNewToken().WithPolicies("default").WithTimeToLive(time.Hour).Do(context.TODO())
+	require.NoError(t, err)
+
+	ctx := ContextWithToken(context.TODO(), parent)
+	child, err := CreateNewToken().WithPolicies("default").WithTimeToLive(time.Hour).Do(ctx)
+	require.NoError(t, err)
+
+	// Revoking the parent revokes the child created with it
+	err = RevokeToken(context.TODO(), parent)
+	require.NoError(t, err)
+	_, err = LookupOther(context.TODO(), child)
+	assert.Error(t, err)
+}
@@ -6,26 +6,32 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"time"
 )
 
 // LookupToken token lookup
 type LookupToken struct {
 	Data struct {
-		Accessor       string            `json:"accessor"`
-		CreationTime   int64             `json:"creation_time"`
-		CreationTTL    int64             `json:"creation_ttl"`
-		DisplayName    string            `json:"display_name"`
-		EntityID       string            `json:"entity_id"`
-		ExpireTime     interface{}       `json:"expire_time"`
-		ExplicitMaxTTL int64             `json:"explicit_max_ttl"`
-		ID             string            `json:"id"` // This is the token auth
-		Meta           map[string]string `json:"meta"`
-		NumUses        int64             `json:"num_uses"`
-		Orphan         bool              `json:"orphan"`
-		Path           string            `json:"path"`
-		Policies       []string          `json:"policies"`
-		TTL            int64             `json:"ttl"`
-		Type           string            `json:"type"`
+		Accessor        string            `json:"accessor"`
+		BoundCIDRs      []string          `json:"bound_cidrs"`
+		CreationTime    int64             `json:"creation_time"` // Unix seconds. Use CreatedAt()
+		CreationTTL     int64             `json:"creation_ttl"`  // Seconds. Use CreationTTLDuration()
+		DisplayName     string            `json:"display_name"`
+		EntityID        string            `json:"entity_id"`
+		ExpireTime      interface{}       `json:"expire_time"`       // RFC3339 string or nil. Use ExpiresAt()
+		ExplicitMaxTTL  int64             `json:"explicit_max_ttl"`  // Seconds. Use ExplicitMaxTTLDuration()
+		ID              string            `json:"id"`                // This is the token auth
+		IssueTime       string            `json:"issue_time"`        // RFC3339. Use IssuedAt()
+		LastRenewalTime int64             `json:"last_renewal_time"` // Unix seconds. Use LastRenewedAt()
+		Meta            map[string]string `json:"meta"`
+		NumUses         int64             `json:"num_uses"`
+		Orphan          bool              `json:"orphan"`
+		Path            string            `json:"path"`
+		Period          int64             `json:"period"` // Seconds. Use PeriodDuration()
+		Policies        []string          `json:"policies"`
+		Renewable       bool              `json:"renewable"` // Whether the token itself is renewable. Use IsRenewable()
+		TTL             int64             `json:"ttl"`       // Seconds left at lookup time. Use TTLDuration()
+		Type            string            `json:"type"`
 	} `json:"data"`
 	RequestID     string      `json:"request_id"`
 	LeaseID       string      `json:"lease_id"`
@@ -36,6 +42,84 @@ type LookupToken struct {
 	Auth          interface{} `json:"auth"`
 }
 
+// CreatedAt returns when the token was created
+func (l LookupToken) CreatedAt() time.Time {
+	return time.Unix(l.Data.CreationTime, 0)
+}
+
+// IssuedAt returns when the token was issued. Zero if Vault did not return it.
+func (l LookupToken) IssuedAt() time.Time {
+	t, _ := time.Parse(time.RFC3339Nano, l.Data.IssueTime)
+	return t
+}
+
+// LastRenewedAt returns when the token was last renewed. Zero if it was never renewed.
+func (l LookupToken) LastRenewedAt() time.Time {
+	if l.Data.LastRenewalTime == 0 {
+		return time.Time{}
+	}
+	return time.Unix(l.Data.LastRenewalTime, 0)
+}
+
+// ExpiresAt returns when the token expires. Zero if it never expires, like root tokens.
+func (l LookupToken) ExpiresAt() time.Time {
+	s, _ := l.Data.ExpireTime.(string)
+	t, _ := time.Parse(time.RFC3339Nano, s)
+	return t
+}
+
+// ExpiresIn returns how long until the token expires, counted from now. Zero if it never expires or already expired.
+func (l LookupToken) ExpiresIn() time.Duration {
+	exp := l.ExpiresAt()
+	if exp.IsZero() {
+		return 0
+	}
+	if d := time.Until(exp); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// TTLDuration returns the time to live left at lookup time
+func (l LookupToken) TTLDuration() time.Duration {
+	return time.Duration(l.Data.TTL) * time.Second
+}
+
+// CreationTTLDuration returns the time to live the token was created with
+func (l LookupToken) CreationTTLDuration() time.Duration {
+	return time.Duration(l.Data.CreationTTL) * time.Second
+}
+
+// ExplicitMaxTTLDuration returns the explicit max time to live. Zero if unset.
+func (l LookupToken) ExplicitMaxTTLDuration() time.Duration {
+	return time.Duration(l.Data.ExplicitMaxTTL) * time.Second
+}
+
+// PeriodDuration returns the token period. Zero if the token is not periodic.
+func (l LookupToken) PeriodDuration() time.Duration {
+	return time.Duration(l.Data.Period) * time.Second
+}
+
+// IsRenewable reports whether the token itself can be renewed
+func (l LookupToken) IsRenewable() bool {
+	return l.Data.Renewable
+}
+
+// IsRoot reports whether the token has the root policy
+func (l LookupToken) IsRoot() bool {
+	return l.HasPolicy("root")
+}
+
+// HasPolicy reports whether the token has policy 'name' attached
+func (l LookupToken) HasPolicy(name string) bool {
+	for _, p := range l.Data.Policies {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
 type sLookupOther struct {
 	Token string `json:"token"`
 }
@@ -59,7 +143,7 @@ func (v *Vault) LookupSelf(ctx context.Context) (lookup LookupToken, err error)
 		return lookup, err
 	}
 	err = json.Unmarshal(body, &lookup)
-	return lookup, nil
+	return lookup, err
 }
 
 // LookupOther lookup information on passed token
@@ -88,5 +172,5 @@ func (v *Vault) LookupOther(ctx context.Context, token string) (lookup LookupTok
 		return lookup, err
 	}
 	err = json.Unmarshal(resBody, &lookup)
-	return lookup, nil
+	return lookup, err
 }
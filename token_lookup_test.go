@@ -2,6 +2,7 @@ package forest
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -22,4 +23,63 @@ func Test_LookupOther(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, token, look.Data.ID)
 	assert.Less(t, look.Data.TTL, int64(3601))
+	assert.True(t, look.IsRenewable())
+	assert.True(t, look.HasPolicy("default"))
+	assert.InDelta(t, float64(time.Hour), float64(look.ExpiresIn()), float64(time.Minute))
+}
+
+func Test_LookupToken_Accessors(t *testing.T) {
+	body := `{"data":{"accessor":"abc","bound_cidrs":["10.0.0.0/8"],"creation_time":1600000000,"creation_ttl":3600,
+	"expire_time":"2999-01-01T00:00:00.000000000Z","explicit_max_ttl":7200,"issue_time":"2020-09-13T12:26:40.123456789Z",
+	"last_renewal_time":1600000100,"period":0,"policies":["default","ms-order"],"renewable":true,"ttl":3500,"type":"service"}}`
+	var look LookupToken
+	require.NoError(t, json.Unmarshal([]byte(body), &look))
+
+	assert.Equal(t, time.Unix(1600000000, 0), look.CreatedAt())
+	assert.Equal(t, 2020, look.IssuedAt().Year())
+	assert.Equal(t, time.Unix(1600000100, 0), look.LastRenewedAt())
+	assert.Equal(t, 2999, look.ExpiresAt().Year())
+	assert.True(t, look.ExpiresIn() > 24*time.Hour)
+	assert.Equal(t, 3500*time.Second, look.TTLDuration())
+	assert.Equal(t, time.Hour, look.CreationTTLDuration())
+	assert.Equal(t, 2*time.Hour, look.ExplicitMaxTTLDuration())
+	assert.Equal(t, []string{"10.0.0.0/8"}, look.Data.BoundCIDRs)
+	assert.True(t, look.IsRenewable())
+	assert.True(t, look.HasPolicy("ms-order"))
+	assert.False(t, look.IsRoot())
+
+	var root LookupToken
+	require.NoError(t, json.Unmarshal([]byte(`{"data":{"expire_time":null,"policies":["root"],"ttl":0}}`), &root))
+	assert.True(t, root.IsRoot())
+	assert.True(t, root.ExpiresAt().IsZero())
+	assert.Equal(t, time.Duration(0), root.ExpiresIn())
+	assert.True(t, root.LastRenewedAt().IsZero())
+}
+
+func Test_LookupSelf_ContextToken(t *testing.T) {
+	token, err := CreateNewToken().WithPolicies("default").WithTimeToLive(1 * time.Hour).Do(context.TODO())
+	require.NoError(t, err)
+	look, err := LookupSelf(ContextWithToken(context.TODO(), token))
+	require.NoError(t, err)
+	assert.Equal(t, token, look.Data.ID)
+}
+
+func Test_ClientWithToken(t *testing.T) {
+	token, err := CreateNewToken().WithPolicies("default").WithTimeToLive(1 * time.Hour).Do(context.TODO())
+	require.NoError(t, err)
+	client, err := ClientWithToken(token)
+	require.NoError(t, err)
+
+	look, err := client.LookupSelf(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, token, look.Data.ID)
+
+	// Default policy cannot read key value store
+	_, err = client.GetKeyValue(context.TODO(), "forest-data")
+	assert.Error(t, err)
+
+	// Global instance is untouched
+	look, err = LookupSelf(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, *testToken, look.Data.ID)
 }
@@ -4,46 +4,80 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type renewToken struct {
-	Token     string `json:"token"`
+	Token     string `json:"token,omitempty"`
+	Accessor  string `json:"accessor,omitempty"`
 	Increment string `json:"increment,omitempty"`
 }
 
 // RenewTokenSelf attempts to renew token registered to self. Cannot renew root token with 0 time to live (never expire).
 func (v *Vault) RenewTokenSelf(ctx context.Context) (err error) {
-	body, err := json.Marshal(renewToken{
-		Token: v.Config.Token,
-	})
-	if err != nil {
-		return
-	}
-	req, err := v.requestGen(ctx, http.MethodPost, "/auth/token/renew", bytes.NewBuffer(body))
-	if err != nil {
-		return
-	}
-	res, err := v.Config.HTTPClient.Do(req)
-	if err != nil {
-		return
-	}
-	defer res.Body.Close()
-	if err := checkErrorResponse(res); err != nil {
-		return err
-	}
+	_, err = v.RenewTokenSelfIncrement(ctx, 0)
 	return
 }
 
 // RenewTokenOther attempts to renew passed token using self's token
 func (v *Vault) RenewTokenOther(ctx context.Context, token string) (err error) {
-	body, err := json.Marshal(renewToken{
-		Token: token,
-	})
+	_, err = v.RenewTokenOtherIncrement(ctx, token, 0)
+	return
+}
+
+// RenewTokenOverride attempts to renew passed token with passed token as auth
+func (v *Vault) RenewTokenOverride(ctx context.Context, token string) (err error) {
+	_, err = v.RenewTokenOverrideIncrement(ctx, token, 0)
+	return
+}
+
+// RenewTokenSelfIncrement attempts to renew token registered to self, asking for 'increment' more time to live.
+// Zero increment follows the token's default. Vault may grant less than asked, capped by max TTL,
+// so check the returned auth for what is actually granted.
+//
+// Self is the token used as auth, so the context token from ContextWithToken is renewed when there is one.
+func (v *Vault) RenewTokenSelfIncrement(ctx context.Context, increment time.Duration) (auth TokenAuth, err error) {
+	return v.renew(ctx, "/auth/token/renew-self", "", renewToken{}, increment)
+}
+
+// RenewTokenOtherIncrement is RenewTokenOther asking for 'increment' more time to live, see RenewTokenSelfIncrement
+func (v *Vault) RenewTokenOtherIncrement(ctx context.Context, token string, increment time.Duration) (auth TokenAuth, err error) {
+	return v.renew(ctx, "/auth/token/renew", "", renewToken{Token: token}, increment)
+}
+
+// RenewTokenOverrideIncrement is RenewTokenOverride asking for 'increment' more time to live, see RenewTokenSelfIncrement
+func (v *Vault) RenewTokenOverrideIncrement(ctx context.Context, token string, increment time.Duration) (auth TokenAuth, err error) {
+	return v.renew(ctx, "/auth/token/renew", token, renewToken{Token: token}, increment)
+}
+
+// RenewTokenAccessorIncrement is RenewTokenAccessor asking for 'increment' more time to live, see RenewTokenSelfIncrement.
+// The returned auth does not contain the client token.
+func (v *Vault) RenewTokenAccessorIncrement(ctx context.Context, accessor string, increment time.Duration) (auth TokenAuth, err error) {
+	return v.renew(ctx, "/auth/token/renew-accessor", "", renewToken{Accessor: accessor}, increment)
+}
+
+// renew sends the renew request. Empty authToken uses the instance token.
+func (v *Vault) renew(ctx context.Context, path, authToken string, body renewToken, increment time.Duration) (auth TokenAuth, err error) {
+	if increment < 0 {
+		return auth, errors.New("increment cannot be negative")
+	}
+	if increment > 0 {
+		body.Increment = formatDuration(increment)
+	}
+	b, err := json.Marshal(body)
 	if err != nil {
 		return
 	}
-	req, err := v.requestGen(ctx, http.MethodPost, "/auth/token/renew", bytes.NewBuffer(body))
+	var req *http.Request
+	if authToken == "" {
+		req, err = v.requestGen(ctx, http.MethodPost, path, bytes.NewBuffer(b))
+	} else {
+		req, err = v.requestGenOverride(ctx, http.MethodPost, path, authToken, bytes.NewBuffer(b))
+	}
 	if err != nil {
 		return
 	}
@@ -53,30 +87,35 @@ func (v *Vault) RenewTokenOther(ctx context.Context, token string) (err error) {
 	}
 	defer res.Body.Close()
 	if err := checkErrorResponse(res); err != nil {
-		return err
+		return auth, err
 	}
-	return
+	var response tokenResponse
+	err = json.NewDecoder(res.Body).Decode(&response)
+	if err != nil {
+		return auth, err
+	}
+	return response.Auth, nil
 }
 
-// RenewTokenOverride attempts to renew passed token with passed token as auth
-func (v *Vault) RenewTokenOverride(ctx context.Context, token string) (err error) {
-	body, err := json.Marshal(renewToken{
-		Token: token,
-	})
-	if err != nil {
-		return
+// formatDuration formats a duration in Vault's duration syntax, like 1h30m or 90s.
+// Vault works in whole seconds, so sub second durations are rounded up.
+func formatDuration(d time.Duration) string {
+	secs := int64(d / time.Second)
+	if d%time.Second > 0 {
+		secs++
 	}
-	req, err := v.requestGenOverride(ctx, http.MethodPost, "/auth/token/renew", token, bytes.NewBuffer(body))
-	if err != nil {
-		return
+	if secs <= 0 {
+		return "0s"
 	}
-	res, err := v.Config.HTTPClient.Do(req)
-	if err != nil {
-		return
+	var sb strings.Builder
+	if h := secs / 3600; h > 0 {
+		sb.WriteString(strconv.FormatInt(h, 10) + "h")
 	}
-	defer res.Body.Close()
-	if err := checkErrorResponse(res); err != nil {
-		return err
+	if m := secs % 3600 / 60; m > 0 {
+		sb.WriteString(strconv.FormatInt(m, 10) + "m")
 	}
-	return
+	if s := secs % 60; s > 0 {
+		sb.WriteString(strconv.FormatInt(s, 10) + "s")
+	}
+	return sb.String()
 }
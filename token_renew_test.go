@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -14,3 +15,48 @@ func Test_RenewTokenOther(t *testing.T) {
 	err = RenewTokenOther(context.TODO(), token)
 	require.NoError(t, err)
 }
+
+func Test_RenewTokenOtherIncrement(t *testing.T) {
+	token, err := CreateNewToken().WithPolicies("default").WithTimeToLive(1 * time.Hour).WithExplicitMaxTTL(2 * time.Hour).Do(context.TODO())
+	require.NoError(t, err)
+
+	auth, err := RenewTokenOtherIncrement(context.TODO(), token, 30*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Minute, auth.LeaseTTL())
+	assert.True(t, auth.Renewable)
+	assert.Contains(t, auth.Policies, "default")
+
+	// Capped by explicit max TTL
+	auth, err = RenewTokenOtherIncrement(context.TODO(), token, 10*time.Hour)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, int64(auth.LeaseTTL()), int64(2*time.Hour))
+}
+
+func Test_RenewTokenSelf_ContextToken(t *testing.T) {
+	token, err := CreateNewToken().WithPolicies("default").WithTimeToLive(10 * time.Minute).Do(context.TODO())
+	require.NoError(t, err)
+	defer RevokeToken(context.TODO(), token)
+	before, err := LookupSelf(context.TODO())
+	require.NoError(t, err)
+
+	auth, err := RenewTokenSelfIncrement(ContextWithToken(context.TODO(), token), time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, token, auth.ClientToken)
+	assert.Equal(t, time.Hour, auth.LeaseTTL())
+
+	look, err := LookupOther(context.TODO(), token)
+	require.NoError(t, err)
+	assert.Greater(t, look.Data.TTL, int64(10*60))
+	after, err := LookupSelf(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, before.Data.ExpireTime, after.Data.ExpireTime)
+}
+
+func Test_FormatDuration(t *testing.T) {
+	assert.Equal(t, "1h", formatDuration(time.Hour))
+	assert.Equal(t, "10m", formatDuration(10*time.Minute))
+	assert.Equal(t, "1m30s", formatDuration(90*time.Second))
+	assert.Equal(t, "1h30m", formatDuration(90*time.Minute))
+	assert.Equal(t, "26h1s", formatDuration(26*time.Hour+time.Second))
+	assert.Equal(t, "1s", formatDuration(time.Millisecond))
+}
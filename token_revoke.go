@@ -32,3 +32,64 @@ func (v *Vault) RevokeToken(ctx context.Context, token string) (err error) {
 	}
 	return
 }
+
+// RevokeSelf revokes the token used in the instance and all of its children.
+// Useful for a shutting down service to clean up its own token. The instance cannot be used afterwards.
+func (v *Vault) RevokeSelf(ctx context.Context) (err error) {
+	req, err := v.requestGen(ctx, http.MethodPost, "/auth/token/revoke-self", nil)
+	if err != nil {
+		return
+	}
+	res, err := v.Config.HTTPClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+	if err := checkErrorResponse(res); err != nil {
+		return err
+	}
+	return
+}
+
+// RevokeOrphan revokes given token string but keeps its children, which become orphans.
+// Token used in the instance must have sudo on auth/token/revoke-orphan.
+func (v *Vault) RevokeOrphan(ctx context.Context, token string) (err error) {
+	body := sLookupOther{
+		Token: token,
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return
+	}
+	req, err := v.requestGen(ctx, http.MethodPost, "/auth/token/revoke-orphan", bytes.NewBuffer(b))
+	if err != nil {
+		return
+	}
+	res, err := v.Config.HTTPClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+	if err := checkErrorResponse(res); err != nil {
+		return err
+	}
+	return
+}
+
+// TidyTokens starts cleaning up left over token entries and leases in the background.
+// Vault returns as soon as the operation starts. Token used in the instance must have sudo on auth/token/tidy.
+func (v *Vault) TidyTokens(ctx context.Context) (err error) {
+	req, err := v.requestGen(ctx, http.MethodPost, "/auth/token/tidy", nil)
+	if err != nil {
+		return
+	}
+	res, err := v.Config.HTTPClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+	if err := checkErrorResponse(res); err != nil {
+		return err
+	}
+	return
+}
@@ -0,0 +1,43 @@
+package forest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RevokeOrphan(t *testing.T) {
+	parent, err := CreateNewToken().WithPolicies("default").WithTimeToLive(1 * time.Hour).Do(context.TODO())
+	require.NoError(t, err)
+	child, err := CreateNewToken().WithPolicies("default").WithTimeToLive(1 * time.Hour).DoOverride(context.TODO(), parent)
+	require.NoError(t, err)
+
+	err = RevokeOrphan(context.TODO(), parent)
+	require.NoError(t, err)
+
+	_, err = LookupOther(context.TODO(), parent)
+	assert.Error(t, err)
+	look, err := LookupOther(context.TODO(), child)
+	require.NoError(t, err)
+	assert.True(t, look.Data.Orphan)
+}
+
+func Test_RevokeSelf(t *testing.T) {
+	token, err := CreateNewToken().WithPolicies("default").WithTimeToLive(1 * time.Hour).Do(context.TODO())
+	require.NoError(t, err)
+	client, err := NewClient(token, WithHost(*testHost))
+	require.NoError(t, err)
+
+	err = client.RevokeSelf(context.TODO())
+	require.NoError(t, err)
+	_, err = LookupOther(context.TODO(), token)
+	assert.Error(t, err)
+}
+
+func Test_TidyTokens(t *testing.T) {
+	err := TidyTokens(context.TODO())
+	require.NoError(t, err)
+}
@@ -0,0 +1,83 @@
+package forest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// TokenRole is a token role, used to create tokens through auth/token/create/:role_name.
+// Use CreateNewToken().WithRole(name) to create a token against it.
+type TokenRole struct {
+	Name                 string
+	AllowedPolicies      []string // Policies tokens of the role may have. Tokens get these when none are requested.
+	DisallowedPolicies   []string
+	Orphan               bool
+	NonRenewable         bool // Vault makes tokens of the role renewable by default
+	PathSuffix           string
+	TokenTTL             time.Duration
+	TokenMaxTTL          time.Duration
+	TokenPeriod          time.Duration
+	TokenBoundCIDRs      []string
+	TokenNoDefaultPolicy bool
+	TokenType            string // "service", "batch", or one of the "default-" variants. Empty uses Vault's default.
+}
+
+type jsonTokenRole struct {
+	AllowedPolicies      []string `json:"allowed_policies"`
+	DisallowedPolicies   []string `json:"disallowed_policies"`
+	Orphan               bool     `json:"orphan"`
+	Renewable            bool     `json:"renewable"`
+	PathSuffix           string   `json:"path_suffix,omitempty"`
+	TokenTTL             string   `json:"token_ttl,omitempty"`
+	TokenMaxTTL          string   `json:"token_max_ttl,omitempty"`
+	TokenPeriod          string   `json:"token_period,omitempty"`
+	TokenBoundCIDRs      []string `json:"token_bound_cidrs,omitempty"`
+	TokenNoDefaultPolicy bool     `json:"token_no_default_policy"`
+	TokenType            string   `json:"token_type,omitempty"`
+}
+
+func optionalDuration(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	return formatDuration(d)
+}
+
+// UpsertTokenRole creates/updates token role 'role.Name'. Instance token needs sudo on auth/token/roles.
+func (v *Vault) UpsertTokenRole(ctx context.Context, role TokenRole) (err error) {
+	if role.Name == "" {
+		return errors.New("token role name cannot be empty")
+	}
+	body := jsonTokenRole{
+		AllowedPolicies:      role.AllowedPolicies,
+		DisallowedPolicies:   role.DisallowedPolicies,
+		Orphan:               role.Orphan,
+		Renewable:            !role.NonRenewable,
+		PathSuffix:           role.PathSuffix,
+		TokenTTL:             optionalDuration(role.TokenTTL),
+		TokenMaxTTL:          optionalDuration(role.TokenMaxTTL),
+		TokenPeriod:          optionalDuration(role.TokenPeriod),
+		TokenBoundCIDRs:      role.TokenBoundCIDRs,
+		TokenNoDefaultPolicy: role.TokenNoDefaultPolicy,
+		TokenType:            role.TokenType,
+	}
+	return v.doJSON(ctx, http.MethodPost, "/auth/token/roles/"+url.PathEscape(role.Name), body, nil)
+}
+
+// ListTokenRoles returns names of every token role
+func (v *Vault) ListTokenRoles(ctx context.Context) (names []string, err error) {
+	var ddd configList
+	err = v.doJSON(ctx, http.MethodGet, "/auth/token/roles?list=true", nil, &ddd)
+	return ddd.Data.Keys, err
+}
+
+// DeleteTokenRole deletes token role 'name'. Tokens already created from it are left untouched.
+func (v *Vault) DeleteTokenRole(ctx context.Context, name string) (err error) {
+	if name == "" {
+		return errors.New("token role name cannot be empty")
+	}
+	return v.doJSON(ctx, http.MethodDelete, "/auth/token/roles/"+url.PathEscape(name), nil, nil)
+}
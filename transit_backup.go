@@ -0,0 +1,79 @@
+package forest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type backupResponse struct {
+	RequestID     string `json:"request_id"`
+	LeaseID       string `json:"lease_id"`
+	Renewable     bool   `json:"renewable"`
+	LeaseDuration int64  `json:"lease_duration"`
+	Data          struct {
+		Backup string `json:"backup"`
+	} `json:"data"`
+	WrapInfo interface{} `json:"wrap_info"`
+	Warnings []string    `json:"warnings"`
+	Auth     interface{} `json:"auth"`
+}
+
+type restoreRequest struct {
+	Backup string `json:"backup"`
+	Force  bool   `json:"force,omitempty"`
+}
+
+// TransitBackup returns a plaintext backup of transit key 'key', including all its versions and configuration.
+// The key must have been created with allow_plaintext_backup and exportable set to true.
+// Treat the returned backup as secret as the key itself.
+func (v *Vault) TransitBackup(ctx context.Context, key string) (backup string, err error) {
+	path := fmt.Sprintf("/%s/backup/%s", v.Config.TransitEngine, key)
+	req, err := v.requestGen(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return
+	}
+	res, err := v.Config.HTTPClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+	if err := checkErrorResponse(res); err != nil {
+		return "", err
+	}
+	var response backupResponse
+	err = json.NewDecoder(res.Body).Decode(&response)
+	if err != nil {
+		return "", err
+	}
+	return response.Data.Backup, nil
+}
+
+// TransitRestore restores a backup made by TransitBackup as transit key 'key'.
+// If 'key' is empty, the key name stored in the backup is used.
+// Restoring over an existing key fails unless 'force' is true.
+func (v *Vault) TransitRestore(ctx context.Context, key, backup string, force bool) (err error) {
+	path := fmt.Sprintf("/%s/restore", v.Config.TransitEngine)
+	if key != "" {
+		path += "/" + key
+	}
+	body, err := json.Marshal(restoreRequest{Backup: backup, Force: force})
+	if err != nil {
+		return
+	}
+	req, err := v.requestGen(ctx, http.MethodPost, path, bytes.NewBuffer(body))
+	if err != nil {
+		return
+	}
+	res, err := v.Config.HTTPClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+	if err := checkErrorResponse(res); err != nil {
+		return err
+	}
+	return
+}
@@ -0,0 +1,29 @@
+package forest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Key 'exportable' must be created with exportable and allow_plaintext_backup set to true
+func Test_TransitExport(t *testing.T) {
+	exported, err := TransitExport(context.TODO(), ExportEncryptionKey, "exportable", "latest")
+	require.NoError(t, err)
+	assert.Equal(t, "exportable", exported.Name)
+	assert.Len(t, exported.Keys, 1)
+}
+
+func Test_TransitBackupRestore(t *testing.T) {
+	backup, err := TransitBackup(context.TODO(), "exportable")
+	require.NoError(t, err)
+	require.NotEmpty(t, backup)
+
+	err = TransitRestore(context.TODO(), "exportable-restored", backup, true)
+	require.NoError(t, err)
+
+	err = TransitRestore(context.TODO(), "exportable-restored", backup, false)
+	assert.Error(t, err)
+}
@@ -0,0 +1,121 @@
+package forest
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const ciphertextPrefix = "vault"
+
+var (
+	// ErrNotCiphertext is returned when a string is not in vault:vN:payload format
+	ErrNotCiphertext = errors.New("not a vault transit ciphertext")
+	// ErrCiphertextTooOld is returned when the ciphertext key version is below the key's min_decryption_version.
+	// Vault will refuse to decrypt it until min_decryption_version is lowered.
+	ErrCiphertextTooOld = errors.New("ciphertext key version is below min_decryption_version")
+	// ErrCiphertextTrimmed is returned when the key version used by the ciphertext has been trimmed from the key.
+	// The ciphertext can no longer be decrypted.
+	ErrCiphertextTrimmed = errors.New("ciphertext key version has been trimmed")
+	// ErrCiphertextTooNew is returned when the ciphertext claims a key version newer than the key's latest version.
+	// Most likely it was produced by a different key.
+	ErrCiphertextTooNew = errors.New("ciphertext key version is newer than the latest key version")
+)
+
+// aeadNonceSize is the nonce size used by aes128-gcm96, aes256-gcm96 and chacha20-poly1305 keys
+const aeadNonceSize = 12
+
+// Ciphertext is a transit ciphertext parsed locally, without calling Vault
+type Ciphertext struct {
+	KeyVersion int    // Version of the key that produced the ciphertext
+	Payload    []byte // Decoded payload after the vault:vN: prefix
+}
+
+// ParseCiphertext parses a transit ciphertext in vault:vN:payload format.
+// The same format is used for derived and convergent keys, so use the key's config to tell them apart.
+func ParseCiphertext(cipherText string) (c Ciphertext, err error) {
+	parts := strings.SplitN(strings.TrimSpace(cipherText), ":", 3)
+	if len(parts) != 3 || parts[0] != ciphertextPrefix || !strings.HasPrefix(parts[1], "v") {
+		return c, ErrNotCiphertext
+	}
+	version := parts[1][1:]
+	c.KeyVersion, err = strconv.Atoi(version)
+	// Atoi accepts a sign, versions are digits only
+	if err != nil || strings.Trim(version, "0123456789") != "" || c.KeyVersion < 1 {
+		return c, fmt.Errorf("%w: invalid key version '%s'", ErrNotCiphertext, parts[1])
+	}
+	c.Payload, err = base64.StdEncoding.DecodeString(parts[2])
+	if err != nil || len(c.Payload) == 0 {
+		return c, fmt.Errorf("%w: invalid payload", ErrNotCiphertext)
+	}
+	return c, nil
+}
+
+// IsCiphertext reports whether the string looks like a transit ciphertext
+func IsCiphertext(cipherText string) bool {
+	_, err := ParseCiphertext(cipherText)
+	return err == nil
+}
+
+// String returns the ciphertext in vault:vN:payload format
+func (c Ciphertext) String() string {
+	return fmt.Sprintf("%s:v%d:%s", ciphertextPrefix, c.KeyVersion, base64.StdEncoding.EncodeToString(c.Payload))
+}
+
+// RequiresContext reports whether decrypting the ciphertext requires the context used when encrypting,
+// which is the case for derived keys.
+func (c Ciphertext) RequiresContext(cfg TransitKeyConfig) bool {
+	return cfg.Derived
+}
+
+// RequiresNonce reports whether decrypting the ciphertext requires the nonce used when encrypting.
+// Only convergent encryption version 1 keys leave the nonce out of the ciphertext.
+func (c Ciphertext) RequiresNonce(cfg TransitKeyConfig) bool {
+	return cfg.ConvergentEncryption && cfg.ConvergentEncryptionVersion == 1
+}
+
+// Nonce returns the nonce embedded in the payload for AEAD keys.
+// Returns nil for keys that do not embed a nonce, like RSA keys or convergent encryption version 1 keys.
+func (c Ciphertext) Nonce(cfg TransitKeyConfig) []byte {
+	switch cfg.Type {
+	case "aes128-gcm96", "aes256-gcm96", "chacha20-poly1305":
+	default:
+		return nil
+	}
+	if c.RequiresNonce(cfg) || len(c.Payload) < aeadNonceSize {
+		return nil
+	}
+	return c.Payload[:aeadNonceSize]
+}
+
+// CheckDecryptable checks the ciphertext key version against the key's config.
+// Use this to find data that will be unreadable before rotating the key or raising min_decryption_version.
+// Returns ErrCiphertextTooOld, ErrCiphertextTrimmed or ErrCiphertextTooNew, or nil if Vault should be able to decrypt it.
+func (c Ciphertext) CheckDecryptable(cfg TransitKeyConfig) error {
+	switch {
+	case cfg.LatestVersion > 0 && c.KeyVersion > cfg.LatestVersion:
+		return ErrCiphertextTooNew
+	case c.KeyVersion < cfg.MinAvailableVersion:
+		return ErrCiphertextTrimmed
+	case c.KeyVersion < cfg.MinDecryptionVersion:
+		return ErrCiphertextTooOld
+	}
+	return nil
+}
+
+// TransitCheckCiphertext parses the ciphertext and checks it against the current config of transit key 'key'.
+// See Ciphertext.CheckDecryptable for the returned errors.
+func (v *Vault) TransitCheckCiphertext(ctx context.Context, key, cipherText string) (err error) {
+	c, err := ParseCiphertext(cipherText)
+	if err != nil {
+		return
+	}
+	cfg, err := v.TransitReadKey(ctx, key)
+	if err != nil {
+		return
+	}
+	return c.CheckDecryptable(cfg)
+}
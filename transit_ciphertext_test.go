@@ -0,0 +1,57 @@
+package forest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseCiphertext(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		raw := `vault:v12:6Xyg+Yk8VDMLhKQTU7J+1FGAaFyk9qTwyB9SgEySEhv4pNKnZ2jEkbBLCAlW`
+		c, err := ParseCiphertext(raw)
+		require.NoError(t, err)
+		assert.Equal(t, 12, c.KeyVersion)
+		assert.Equal(t, raw, c.String())
+		assert.True(t, IsCiphertext(raw))
+	})
+
+	for _, raw := range []string{"", "hello", "vault:1:YWJj", "vault:v0:YWJj", "vault:vx:YWJj", "vault:v+1:YWJj", "vault:v-1:YWJj", "vault:v:YWJj", "vault:v1:", "vault:v1:not base64", "tink:v1:YWJj"} {
+		assert.False(t, IsCiphertext(raw), raw)
+		_, err := ParseCiphertext(raw)
+		assert.True(t, errors.Is(err, ErrNotCiphertext), raw)
+	}
+}
+
+func Test_Ciphertext_CheckDecryptable(t *testing.T) {
+	cfg := TransitKeyConfig{Type: "aes256-gcm96", LatestVersion: 5, MinDecryptionVersion: 3, MinAvailableVersion: 2}
+	assert.Equal(t, ErrCiphertextTrimmed, Ciphertext{KeyVersion: 1}.CheckDecryptable(cfg))
+	assert.Equal(t, ErrCiphertextTooOld, Ciphertext{KeyVersion: 2}.CheckDecryptable(cfg))
+	assert.NoError(t, Ciphertext{KeyVersion: 3}.CheckDecryptable(cfg))
+	assert.NoError(t, Ciphertext{KeyVersion: 5}.CheckDecryptable(cfg))
+	assert.Equal(t, ErrCiphertextTooNew, Ciphertext{KeyVersion: 6}.CheckDecryptable(cfg))
+}
+
+func Test_Ciphertext_Forms(t *testing.T) {
+	c := Ciphertext{KeyVersion: 1, Payload: []byte("0123456789abcdefghijklmnopqrstuvwxyz")}
+	assert.Equal(t, []byte("0123456789ab"), c.Nonce(TransitKeyConfig{Type: "aes256-gcm96"}))
+	assert.False(t, c.RequiresContext(TransitKeyConfig{Type: "aes256-gcm96"}))
+
+	derived := TransitKeyConfig{Type: "aes256-gcm96", Derived: true, ConvergentEncryption: true, ConvergentEncryptionVersion: 1}
+	assert.True(t, c.RequiresContext(derived))
+	assert.True(t, c.RequiresNonce(derived))
+	assert.Nil(t, c.Nonce(derived))
+
+	assert.Nil(t, c.Nonce(TransitKeyConfig{Type: "rsa-2048"}))
+}
+
+func Test_TransitCheckCiphertext(t *testing.T) {
+	err := TransitCheckCiphertext(context.TODO(), "aes", `vault:v1:6Xyg+Yk8VDMLhKQTU7J+1FGAaFyk9qTwyB9SgEySEhv4pNKnZ2jEkbBLCAlW`)
+	require.NoError(t, err)
+
+	err = TransitCheckCiphertext(context.TODO(), "aes", `vault:v999:6Xyg+Yk8VDMLhKQTU7J+1FGAaFyk9qTwyB9SgEySEhv4pNKnZ2jEkbBLCAlW`)
+	assert.Equal(t, ErrCiphertextTooNew, err)
+}
@@ -0,0 +1,90 @@
+package forest
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// DataKeyType determines what Vault returns when generating a data key
+type DataKeyType string
+
+const (
+	// DataKeyPlaintext returns the plaintext data key alongside the wrapped one
+	DataKeyPlaintext DataKeyType = "plaintext"
+	// DataKeyWrapped only returns the wrapped data key
+	DataKeyWrapped DataKeyType = "wrapped"
+)
+
+type dataKeyRequest struct {
+	Context string `json:"context,omitempty"`
+	Nonce   string `json:"nonce,omitempty"`
+	Bits    int    `json:"bits,omitempty"`
+}
+
+type dataKeyResponse struct {
+	RequestID     string `json:"request_id"`
+	LeaseID       string `json:"lease_id"`
+	Renewable     bool   `json:"renewable"`
+	LeaseDuration int64  `json:"lease_duration"`
+	Data          struct {
+		Plaintext  string `json:"plaintext"`
+		Ciphertext string `json:"ciphertext"`
+		KeyVersion int    `json:"key_version"`
+	} `json:"data"`
+	WrapInfo interface{} `json:"wrap_info"`
+	Warnings []string    `json:"warnings"`
+	Auth     interface{} `json:"auth"`
+}
+
+// DataKey is a key generated by transit engine to encrypt data locally
+type DataKey struct {
+	Plaintext  []byte // Raw key. Empty if generated with DataKeyWrapped. Never store this.
+	Ciphertext string // The key wrapped by the transit key. Store this alongside the encrypted data.
+	KeyVersion int    // Version of the transit key used to wrap the data key
+}
+
+// TransitGenerateDataKey generates a new high entropy data key wrapped by transit key 'key'.
+// 'bits' may be 128, 256 or 512. Zero uses Vault's default of 256.
+//
+// Use TransitDecrypt on DataKey.Ciphertext to get the plaintext key back later.
+func (v *Vault) TransitGenerateDataKey(ctx context.Context, key string, keyType DataKeyType, bits int) (dk DataKey, err error) {
+	if keyType != DataKeyPlaintext && keyType != DataKeyWrapped {
+		return dk, fmt.Errorf("unknown data key type: '%s'", keyType)
+	}
+	path := fmt.Sprintf("/%s/datakey/%s/%s", v.Config.TransitEngine, keyType, key)
+	reqBody, err := json.Marshal(dataKeyRequest{Bits: bits})
+	if err != nil {
+		return
+	}
+	req, err := v.requestGen(ctx, http.MethodPost, path, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return
+	}
+	res, err := v.Config.HTTPClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+	if err := checkErrorResponse(res); err != nil {
+		return dk, err
+	}
+	var response dataKeyResponse
+	err = json.NewDecoder(res.Body).Decode(&response)
+	if err != nil {
+		return
+	}
+	if response.Data.Plaintext != "" {
+		dk.Plaintext, err = base64.StdEncoding.DecodeString(response.Data.Plaintext)
+		if err != nil {
+			return dk, errors.New("Fail to decode data key")
+		}
+	}
+	dk.Ciphertext = response.Data.Ciphertext
+	dk.KeyVersion = response.Data.KeyVersion
+	return
+}
@@ -35,6 +35,16 @@ type decryptRequest struct {
 // TransitDecrypt decrypts a transit encrypted payload.
 // If decyprting a big ciphertext like if decrypted it's actually an image, please use TransitDecryptStream.
 func (v *Vault) TransitDecrypt(ctx context.Context, key, cipherText string) (data []byte, err error) {
+	s, err := v.transitDecryptRaw(ctx, key, cipherText)
+	if err != nil {
+		return nil, err
+	}
+	trimmed := strings.TrimSpace(string(s))
+	return []byte(trimmed), nil
+}
+
+// transitDecryptRaw decrypts without trimming the result, so binary payloads like data keys stay intact.
+func (v *Vault) transitDecryptRaw(ctx context.Context, key, cipherText string) (data []byte, err error) {
 	path := fmt.Sprintf("/%s/decrypt/%s", v.Config.TransitEngine, key)
 	trimmedCipher := strings.TrimSpace(cipherText)
 	body := decryptRequest{
@@ -57,48 +67,40 @@ func (v *Vault) TransitDecrypt(ctx context.Context, key, cipherText string) (dat
 		return nil, err
 	}
 	var response decryptResponse
-	json.NewDecoder(res.Body).Decode(&response)
+	err = json.NewDecoder(res.Body).Decode(&response)
 	if err != nil {
 		return nil, err
 	}
-	s, err := base64.StdEncoding.DecodeString(response.Data.Plaintext)
+	data, err = base64.StdEncoding.DecodeString(response.Data.Plaintext)
 	if err != nil {
-		return data, errors.New("Fail to decode payload")
+		return nil, errors.New("Fail to decode payload")
 	}
-	trimmed := strings.TrimSpace(string(s))
-	return []byte(trimmed), nil
+	return data, nil
 }
 
 // TransitDecryptStream decrypts a transit encrypted payload in streaming manner.
 // Best usage is if you expect a big ciphertext from whatever your source is.
+//
+// Cipher is sent while it's being read, and the plaintext is decoded while the returned reader is read,
+// so memory usage stays the same regardless of payload size. Read the returned reader until EOF to release the connection.
 func (v *Vault) TransitDecryptStream(ctx context.Context, key string, cipher io.Reader) (payload io.Reader, err error) {
 	path := fmt.Sprintf("/%s/decrypt/%s", v.Config.TransitEngine, key)
-	// Initiate buf json holder
-	buf := bytes.NewBufferString(`{"ciphertext":"`)
-	// Starts the payload encryption
-	_, err = io.Copy(buf, cipher)
-	if err != nil {
-		return nil, err
-	}
-
-	_, err = buf.WriteString(`"}`)
-	if err != nil {
-		return nil, err
-	}
-	req, err := v.requestGen(ctx, http.MethodPost, path, buf)
+	body := pipeJSONString("ciphertext", cipher, false)
+	req, err := v.requestGen(ctx, http.MethodPost, path, body)
 	if err != nil {
+		body.Close()
 		return nil, err
 	}
 	res, err := v.Config.HTTPClient.Do(req)
 	if err != nil {
+		body.CloseWithError(err)
 		return nil, err
 	}
-	defer res.Body.Close()
-	p := newStreamInBetween(`"plaintext":"`, '"')
-	_, err = io.Copy(p, res.Body)
-	if err != nil && err != io.ErrShortWrite {
-		return
+	if err := checkErrorResponse(res); err != nil {
+		res.Body.Close()
+		return nil, err
 	}
+	p := newJSONStringStream(res.Body, "data", "plaintext")
 	payload = base64.NewDecoder(base64.StdEncoding, p)
 	return payload, nil
 }
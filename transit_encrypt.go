@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"strings"
 )
 
 // Plaintext is the required field
@@ -37,45 +36,6 @@ type encryptResponse struct {
 	Auth     interface{} `json:"auth"`
 }
 
-type encryptResponseStream struct {
-	startData bool
-	initiate  strings.Builder
-	temp      *bytes.Buffer
-}
-
-func (e *encryptResponseStream) Read(p []byte) (n int, err error) {
-	return e.temp.Read(p)
-}
-
-func (e *encryptResponseStream) Write(p []byte) (n int, err error) {
-	if e.temp == nil {
-		e.temp = bytes.NewBuffer(nil)
-	}
-	for _, b := range p {
-		if e.startData {
-			// Stop early since we hit the closing quote
-			if b == '"' {
-				return 0, nil
-			}
-			err = e.temp.WriteByte(b)
-			if err != nil {
-				return 0, err
-			}
-		} else {
-			err = e.initiate.WriteByte(b)
-			if err != nil {
-				return 0, err
-			}
-			dd := e.initiate.String()
-			if strings.Contains(dd, `"ciphertext":"`) {
-				e.startData = true
-			}
-		}
-		n++
-	}
-	return
-}
-
 // TransitEncrypt will encrypt payload for sending somewhere else. 'key' is the encryptor name.
 func (v *Vault) TransitEncrypt(ctx context.Context, key string, payload []byte) (cipherText string, err error) {
 	encoded := base64.StdEncoding.EncodeToString(payload)
@@ -110,44 +70,25 @@ func (v *Vault) TransitEncrypt(ctx context.Context, key string, payload []byte)
 
 // TransitEncryptStream will encrypt payload in stream manner to prevent memory overload on huge number of operation.
 // Use this function for big files. The returned io Reader is a stream of pure encoded vault data without bells and whistles of JSON.
+//
+// Payload is encoded and sent while it's being read, and the response is parsed while the returned reader is read,
+// so memory usage stays the same regardless of payload size. Read the returned reader until EOF to release the connection.
 func (v *Vault) TransitEncryptStream(ctx context.Context, key string, payload io.Reader) (io.Reader, error) {
-	// Initiate buf json holder
-	buf := bytes.NewBufferString(`{"plaintext":"`)
-
-	// Creates the encoder. Any data written to encoder will be written to buf in encoded fashion
-	encoded := base64.NewEncoder(base64.StdEncoding, buf)
-
-	// Starts the payload encryption
-	_, err := io.Copy(encoded, payload)
-	if err != nil {
-		return nil, err
-	}
-	// Close has to be called now to trigger adding remaining data to buf
-	err = encoded.Close()
-	if err != nil {
-		return nil, err
-	}
-
-	// Enclose the json stream
-	_, err = buf.WriteString(`"}`)
-	if err != nil {
-		return nil, err
-	}
-
+	body := pipeJSONString("plaintext", payload, true)
 	path := fmt.Sprintf("/%s/encrypt/%s", v.Config.TransitEngine, key)
-	req, err := v.requestGen(ctx, http.MethodPost, path, buf)
+	req, err := v.requestGen(ctx, http.MethodPost, path, body)
 	if err != nil {
+		body.Close()
 		return nil, err
 	}
 	res, err := v.Config.HTTPClient.Do(req)
 	if err != nil {
+		body.CloseWithError(err)
 		return nil, err
 	}
-	defer res.Body.Close()
-	cipher := newStreamInBetween(`"ciphertext":"`, '"')
-	_, err = io.Copy(cipher, res.Body)
-	if err != nil && err != io.ErrShortWrite {
+	if err := checkErrorResponse(res); err != nil {
+		res.Body.Close()
 		return nil, err
 	}
-	return cipher, nil
+	return newJSONStringStream(res.Body, "data", "ciphertext"), nil
 }
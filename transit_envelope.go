@@ -0,0 +1,260 @@
+package forest
+
+import (
+	"bufio"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// Envelope format, all integers are big endian:
+//
+//	magic "FRST" | version (1) | chunk size (4) | nonce prefix (7) | wrapped key length (2) | wrapped key
+//
+// followed by AES-256-GCM sealed chunks of chunk size plaintext each (the last one may be shorter or empty).
+// Chunk nonce is nonce prefix | chunk counter (4) | last chunk flag (1), and the whole header is used
+// as additional data so neither the header, chunk order nor truncation can be tampered with.
+const (
+	envelopeMagic           = "FRST"
+	envelopeVersion         = 1
+	envelopeChunkSize       = 64 * 1024
+	envelopeMaxChunkSize    = 16 * 1024 * 1024
+	envelopeNoncePrefixSize = 7
+	envelopeFixedHeaderSize = len(envelopeMagic) + 1 + 4 + envelopeNoncePrefixSize + 2
+)
+
+type envelopeHeader struct {
+	chunkSize   uint32
+	noncePrefix []byte
+	wrappedKey  string
+}
+
+func (h envelopeHeader) marshal() []byte {
+	b := make([]byte, 0, envelopeFixedHeaderSize+len(h.wrappedKey))
+	b = append(b, envelopeMagic...)
+	b = append(b, envelopeVersion)
+	b = append(b, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(b[len(b)-4:], h.chunkSize)
+	b = append(b, h.noncePrefix...)
+	b = append(b, 0, 0)
+	binary.BigEndian.PutUint16(b[len(b)-2:], uint16(len(h.wrappedKey)))
+	b = append(b, h.wrappedKey...)
+	return b
+}
+
+func readEnvelopeHeader(r io.Reader) (h envelopeHeader, raw []byte, err error) {
+	fixed := make([]byte, envelopeFixedHeaderSize)
+	if _, err = io.ReadFull(r, fixed); err != nil {
+		return h, nil, fmt.Errorf("envelope: fail to read header: %w", err)
+	}
+	if string(fixed[:len(envelopeMagic)]) != envelopeMagic {
+		return h, nil, errors.New("envelope: not a forest envelope")
+	}
+	offset := len(envelopeMagic)
+	if fixed[offset] != envelopeVersion {
+		return h, nil, fmt.Errorf("envelope: unsupported version %d", fixed[offset])
+	}
+	offset++
+	h.chunkSize = binary.BigEndian.Uint32(fixed[offset:])
+	if h.chunkSize == 0 || h.chunkSize > envelopeMaxChunkSize {
+		return h, nil, errors.New("envelope: invalid chunk size")
+	}
+	offset += 4
+	h.noncePrefix = fixed[offset : offset+envelopeNoncePrefixSize]
+	offset += envelopeNoncePrefixSize
+	keyLen := binary.BigEndian.Uint16(fixed[offset:])
+	wrapped := make([]byte, keyLen)
+	if _, err = io.ReadFull(r, wrapped); err != nil {
+		return h, nil, fmt.Errorf("envelope: fail to read wrapped key: %w", err)
+	}
+	h.wrappedKey = string(wrapped)
+	return h, append(fixed, wrapped...), nil
+}
+
+func envelopeNonce(prefix []byte, counter uint32, last bool) []byte {
+	nonce := make([]byte, 0, 12)
+	nonce = append(nonce, prefix...)
+	nonce = append(nonce, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(nonce[envelopeNoncePrefixSize:], counter)
+	if last {
+		return append(nonce, 1)
+	}
+	return append(nonce, 0)
+}
+
+func newEnvelopeAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// TransitEnvelopeEncrypt encrypts payload locally using envelope encryption, so the payload never hits Vault.
+// A fresh data key is generated from transit key 'key', and the payload is encrypted with AES-GCM in fixed size
+// authenticated chunks. The data key wrapped by Vault is written in the header of the returned stream,
+// so only TransitEnvelopeDecrypt and the same transit key is needed to read it back.
+//
+// Memory usage is constant regardless of payload size. Use this for files too big for TransitEncryptStream.
+func (v *Vault) TransitEnvelopeEncrypt(ctx context.Context, key string, payload io.Reader) (io.Reader, error) {
+	dk, err := v.TransitGenerateDataKey(ctx, key, DataKeyPlaintext, 256)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newEnvelopeAEAD(dk.Plaintext)
+	for i := range dk.Plaintext {
+		dk.Plaintext[i] = 0
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(dk.Ciphertext) > math.MaxUint16 {
+		return nil, errors.New("envelope: wrapped key too long")
+	}
+	h := envelopeHeader{
+		chunkSize:   envelopeChunkSize,
+		noncePrefix: make([]byte, envelopeNoncePrefixSize),
+		wrappedKey:  dk.Ciphertext,
+	}
+	if _, err = io.ReadFull(rand.Reader, h.noncePrefix); err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeEnvelope(ctx, pw, aead, h, payload))
+	}()
+	return pr, nil
+}
+
+func writeEnvelope(ctx context.Context, w io.Writer, aead cipher.AEAD, h envelopeHeader, payload io.Reader) error {
+	header := h.marshal()
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	br := bufio.NewReaderSize(payload, int(h.chunkSize))
+	buf := make([]byte, h.chunkSize)
+	sealed := make([]byte, 0, int(h.chunkSize)+aead.Overhead())
+	for counter := uint32(0); ; counter++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n, err := io.ReadFull(br, buf)
+		last := false
+		switch err {
+		case io.EOF, io.ErrUnexpectedEOF:
+			last = true
+		case nil:
+			if _, err := br.Peek(1); err == io.EOF {
+				last = true
+			} else if err != nil {
+				return err
+			}
+		default:
+			return err
+		}
+		if !last && counter == math.MaxUint32 {
+			return errors.New("envelope: payload too large")
+		}
+		sealed = aead.Seal(sealed[:0], envelopeNonce(h.noncePrefix, counter, last), buf[:n], header)
+		if _, err := w.Write(sealed); err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+	}
+}
+
+type envelopeReader struct {
+	src     *bufio.Reader
+	aead    cipher.AEAD
+	header  []byte
+	prefix  []byte
+	chunk   []byte
+	plain   []byte
+	pending []byte
+	counter uint32
+	done    bool
+	err     error
+}
+
+func (e *envelopeReader) Read(p []byte) (n int, err error) {
+	for len(e.pending) == 0 {
+		if e.err != nil {
+			return 0, e.err
+		}
+		if e.done {
+			return 0, io.EOF
+		}
+		e.err = e.next()
+	}
+	n = copy(p, e.pending)
+	e.pending = e.pending[n:]
+	return n, nil
+}
+
+func (e *envelopeReader) next() error {
+	n, err := io.ReadFull(e.src, e.chunk)
+	last := false
+	switch err {
+	case io.EOF, io.ErrUnexpectedEOF:
+		last = true
+	case nil:
+		if _, err := e.src.Peek(1); err == io.EOF {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	default:
+		return err
+	}
+	if n < e.aead.Overhead() {
+		return errors.New("envelope: truncated ciphertext")
+	}
+	e.plain, err = e.aead.Open(e.plain[:0], envelopeNonce(e.prefix, e.counter, last), e.chunk[:n], e.header)
+	if err != nil {
+		return errors.New("envelope: message authentication failed or ciphertext truncated")
+	}
+	e.pending = e.plain
+	e.counter++
+	e.done = last
+	return nil
+}
+
+// TransitEnvelopeDecrypt decrypts a stream produced by TransitEnvelopeEncrypt.
+// The wrapped data key in the header is unwrapped using transit key 'key', then the payload is decrypted
+// chunk by chunk as the returned reader is read. Every chunk is authenticated before it's returned,
+// and a truncated or tampered stream results in an error instead of partial data.
+func (v *Vault) TransitEnvelopeDecrypt(ctx context.Context, key string, cipherStream io.Reader) (io.Reader, error) {
+	src := bufio.NewReader(cipherStream)
+	h, header, err := readEnvelopeHeader(src)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := v.transitDecryptRaw(ctx, key, h.wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newEnvelopeAEAD(dataKey)
+	for i := range dataKey {
+		dataKey[i] = 0
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &envelopeReader{
+		src:    src,
+		aead:   aead,
+		header: header,
+		prefix: h.noncePrefix,
+		chunk:  make([]byte, int(h.chunkSize)+aead.Overhead()),
+		plain:  make([]byte, 0, h.chunkSize),
+	}, nil
+}
@@ -0,0 +1,61 @@
+package forest
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TransitEnvelope(t *testing.T) {
+	want := make([]byte, 3*envelopeChunkSize+123)
+	_, err := rand.Read(want)
+	require.NoError(t, err)
+	var cipher []byte
+	t.Run("Envelope Encrypt", func(t *testing.T) {
+		data, err := TransitEnvelopeEncrypt(context.TODO(), "aes", bytes.NewReader(want))
+		require.NoError(t, err)
+		got, err := ioutil.ReadAll(data)
+		require.NoError(t, err)
+		require.Contains(t, string(got), "vault:v1")
+		require.NotContains(t, string(got), string(want[:32]))
+		cipher = got
+	})
+
+	t.Run("Envelope Decrypt", func(t *testing.T) {
+		if len(cipher) == 0 {
+			t.Skip("envelope encryption failed")
+		}
+		data, err := TransitEnvelopeDecrypt(context.TODO(), "aes", bytes.NewReader(cipher))
+		require.NoError(t, err)
+		got, err := ioutil.ReadAll(data)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("Envelope Decrypt Truncated", func(t *testing.T) {
+		if len(cipher) == 0 {
+			t.Skip("envelope encryption failed")
+		}
+		data, err := TransitEnvelopeDecrypt(context.TODO(), "aes", bytes.NewReader(cipher[:len(cipher)-200]))
+		require.NoError(t, err)
+		_, err = ioutil.ReadAll(data)
+		assert.Error(t, err)
+	})
+}
+
+func Test_TransitGenerateDataKey(t *testing.T) {
+	dk, err := TransitGenerateDataKey(context.TODO(), "aes", DataKeyPlaintext, 256)
+	require.NoError(t, err)
+	assert.Len(t, dk.Plaintext, 32)
+	assert.Contains(t, dk.Ciphertext, "vault:v")
+
+	wrapped, err := TransitGenerateDataKey(context.TODO(), "aes", DataKeyWrapped, 0)
+	require.NoError(t, err)
+	assert.Empty(t, wrapped.Plaintext)
+	assert.Contains(t, wrapped.Ciphertext, "vault:v")
+}
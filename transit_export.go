@@ -0,0 +1,65 @@
+package forest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ExportKeyType is the type of key to export
+type ExportKeyType string
+
+const (
+	// ExportEncryptionKey exports the encryption key
+	ExportEncryptionKey ExportKeyType = "encryption-key"
+	// ExportSigningKey exports the signing key
+	ExportSigningKey ExportKeyType = "signing-key"
+	// ExportHMACKey exports the HMAC key
+	ExportHMACKey ExportKeyType = "hmac-key"
+)
+
+// ExportedKey is the result of TransitExport
+type ExportedKey struct {
+	Name string            `json:"name"`
+	Type string            `json:"type"`
+	Keys map[string]string `json:"keys"` // Key version to key value
+}
+
+type exportResponse struct {
+	RequestID     string      `json:"request_id"`
+	LeaseID       string      `json:"lease_id"`
+	Renewable     bool        `json:"renewable"`
+	LeaseDuration int64       `json:"lease_duration"`
+	Data          ExportedKey `json:"data"`
+	WrapInfo      interface{} `json:"wrap_info"`
+	Warnings      []string    `json:"warnings"`
+	Auth          interface{} `json:"auth"`
+}
+
+// TransitExport exports transit key 'key'. The key must have been created with exportable set to true.
+// 'version' is the key version to export, "latest" for the latest one, or empty for all versions.
+func (v *Vault) TransitExport(ctx context.Context, keyType ExportKeyType, key, version string) (exported ExportedKey, err error) {
+	path := fmt.Sprintf("/%s/export/%s/%s", v.Config.TransitEngine, keyType, key)
+	if version != "" {
+		path += "/" + version
+	}
+	req, err := v.requestGen(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return
+	}
+	res, err := v.Config.HTTPClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+	if err := checkErrorResponse(res); err != nil {
+		return exported, err
+	}
+	var response exportResponse
+	err = json.NewDecoder(res.Body).Decode(&response)
+	if err != nil {
+		return exported, err
+	}
+	return response.Data, nil
+}
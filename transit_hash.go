@@ -0,0 +1,83 @@
+package forest
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// HashAlgorithm is the algorithm used by TransitHash
+type HashAlgorithm string
+
+const (
+	// HashSHA2224 sha2-224
+	HashSHA2224 HashAlgorithm = "sha2-224"
+	// HashSHA2256 sha2-256. Vault's default.
+	HashSHA2256 HashAlgorithm = "sha2-256"
+	// HashSHA2384 sha2-384
+	HashSHA2384 HashAlgorithm = "sha2-384"
+	// HashSHA2512 sha2-512
+	HashSHA2512 HashAlgorithm = "sha2-512"
+	// HashSHA3224 sha3-224
+	HashSHA3224 HashAlgorithm = "sha3-224"
+	// HashSHA3256 sha3-256
+	HashSHA3256 HashAlgorithm = "sha3-256"
+	// HashSHA3384 sha3-384
+	HashSHA3384 HashAlgorithm = "sha3-384"
+	// HashSHA3512 sha3-512
+	HashSHA3512 HashAlgorithm = "sha3-512"
+)
+
+type hashRequest struct {
+	Input     string        `json:"input"`
+	Algorithm HashAlgorithm `json:"algorithm,omitempty"`
+	Format    OutputFormat  `json:"format,omitempty"`
+}
+
+type hashResponse struct {
+	RequestID     string `json:"request_id"`
+	LeaseID       string `json:"lease_id"`
+	Renewable     bool   `json:"renewable"`
+	LeaseDuration int64  `json:"lease_duration"`
+	Data          struct {
+		Sum string `json:"sum"`
+	} `json:"data"`
+	WrapInfo interface{} `json:"wrap_info"`
+	Warnings []string    `json:"warnings"`
+	Auth     interface{} `json:"auth"`
+}
+
+// TransitHash hashes payload using the transit engine and returns the sum encoded in given format.
+// Empty algorithm and format follow Vault's default, which is sha2-256 in hex.
+func (v *Vault) TransitHash(ctx context.Context, payload []byte, algorithm HashAlgorithm, format OutputFormat) (sum string, err error) {
+	path := fmt.Sprintf("/%s/hash", v.Config.TransitEngine)
+	reqBody, err := json.Marshal(hashRequest{
+		Input:     base64.StdEncoding.EncodeToString(payload),
+		Algorithm: algorithm,
+		Format:    format,
+	})
+	if err != nil {
+		return
+	}
+	req, err := v.requestGen(ctx, http.MethodPost, path, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return
+	}
+	res, err := v.Config.HTTPClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+	if err := checkErrorResponse(res); err != nil {
+		return "", err
+	}
+	var response hashResponse
+	err = json.NewDecoder(res.Body).Decode(&response)
+	if err != nil {
+		return "", err
+	}
+	return response.Data.Sum, nil
+}
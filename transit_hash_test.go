@@ -0,0 +1,29 @@
+package forest
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TransitHash(t *testing.T) {
+	payload := []byte(`{"ayam":"kuning","harga":29000}`)
+	t.Run("Default Algorithm", func(t *testing.T) {
+		sum, err := TransitHash(context.TODO(), payload, "", "")
+		require.NoError(t, err)
+		want := sha256.Sum256(payload)
+		assert.Equal(t, hex.EncodeToString(want[:]), sum)
+	})
+	t.Run("SHA2-512 Base64", func(t *testing.T) {
+		sum, err := TransitHash(context.TODO(), payload, HashSHA2512, FormatBase64)
+		require.NoError(t, err)
+		want := sha512.Sum512(payload)
+		assert.Equal(t, base64.StdEncoding.EncodeToString(want[:]), sum)
+	})
+}
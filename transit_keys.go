@@ -0,0 +1,63 @@
+package forest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// TransitKeyConfig is the configuration of a transit key
+type TransitKeyConfig struct {
+	Name                        string                     `json:"name"`
+	Type                        string                     `json:"type"`
+	Derived                     bool                       `json:"derived"`
+	ConvergentEncryption        bool                       `json:"convergent_encryption"`
+	ConvergentEncryptionVersion int                        `json:"convergent_encryption_version"`
+	Exportable                  bool                       `json:"exportable"`
+	AllowPlaintextBackup        bool                       `json:"allow_plaintext_backup"`
+	DeletionAllowed             bool                       `json:"deletion_allowed"`
+	LatestVersion               int                        `json:"latest_version"`
+	MinAvailableVersion         int                        `json:"min_available_version"`
+	MinDecryptionVersion        int                        `json:"min_decryption_version"`
+	MinEncryptionVersion        int                        `json:"min_encryption_version"`
+	SupportsEncryption          bool                       `json:"supports_encryption"`
+	SupportsDecryption          bool                       `json:"supports_decryption"`
+	SupportsDerivation          bool                       `json:"supports_derivation"`
+	SupportsSigning             bool                       `json:"supports_signing"`
+	Keys                        map[string]json.RawMessage `json:"keys"` // Key version to creation time, or key info for asymmetric keys
+}
+
+type transitKeyResponse struct {
+	RequestID     string           `json:"request_id"`
+	LeaseID       string           `json:"lease_id"`
+	Renewable     bool             `json:"renewable"`
+	LeaseDuration int64            `json:"lease_duration"`
+	Data          TransitKeyConfig `json:"data"`
+	WrapInfo      interface{}      `json:"wrap_info"`
+	Warnings      []string         `json:"warnings"`
+	Auth          interface{}      `json:"auth"`
+}
+
+// TransitReadKey returns the configuration of transit key 'key'. Key material is never returned.
+func (v *Vault) TransitReadKey(ctx context.Context, key string) (cfg TransitKeyConfig, err error) {
+	path := fmt.Sprintf("/%s/keys/%s", v.Config.TransitEngine, key)
+	req, err := v.requestGen(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return
+	}
+	res, err := v.Config.HTTPClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+	if err := checkErrorResponse(res); err != nil {
+		return cfg, err
+	}
+	var response transitKeyResponse
+	err = json.NewDecoder(res.Body).Decode(&response)
+	if err != nil {
+		return cfg, err
+	}
+	return response.Data, nil
+}
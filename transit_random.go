@@ -0,0 +1,93 @@
+package forest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// OutputFormat is the encoding Vault uses for returned bytes
+type OutputFormat string
+
+const (
+	// FormatBase64 encodes the result in base64. Vault's default.
+	FormatBase64 OutputFormat = "base64"
+	// FormatHex encodes the result in hex
+	FormatHex OutputFormat = "hex"
+)
+
+// RandomSource is the source of the random bytes
+type RandomSource string
+
+const (
+	// RandomSourcePlatform uses the platform's entropy source. Vault's default.
+	RandomSourcePlatform RandomSource = "platform"
+	// RandomSourceSeal uses the seal's entropy source. Requires Vault Enterprise with an entropy augmented seal.
+	RandomSourceSeal RandomSource = "seal"
+	// RandomSourceAll mixes both platform and seal entropy
+	RandomSourceAll RandomSource = "all"
+)
+
+type randomRequest struct {
+	Bytes  int          `json:"bytes,omitempty"`
+	Format OutputFormat `json:"format,omitempty"`
+}
+
+type randomResponse struct {
+	RequestID     string `json:"request_id"`
+	LeaseID       string `json:"lease_id"`
+	Renewable     bool   `json:"renewable"`
+	LeaseDuration int64  `json:"lease_duration"`
+	Data          struct {
+		RandomBytes string `json:"random_bytes"`
+	} `json:"data"`
+	WrapInfo interface{} `json:"wrap_info"`
+	Warnings []string    `json:"warnings"`
+	Auth     interface{} `json:"auth"`
+}
+
+// GenerateRandom returns 'n' random bytes from Vault's sys/tools/random endpoint, encoded in given format.
+// Zero 'n', empty format and source follow Vault's default, which is 32 bytes in base64 from platform source.
+func (v *Vault) GenerateRandom(ctx context.Context, n int, format OutputFormat, source RandomSource) (random string, err error) {
+	return v.random(ctx, "/sys/tools/random", n, format, source)
+}
+
+// TransitRandom returns 'n' random bytes from the transit engine, encoded in given format.
+// Zero 'n', empty format and source follow Vault's default, which is 32 bytes in base64 from platform source.
+func (v *Vault) TransitRandom(ctx context.Context, n int, format OutputFormat, source RandomSource) (random string, err error) {
+	return v.random(ctx, fmt.Sprintf("/%s/random", v.Config.TransitEngine), n, format, source)
+}
+
+func (v *Vault) random(ctx context.Context, path string, n int, format OutputFormat, source RandomSource) (random string, err error) {
+	if n < 0 {
+		return "", errors.New("number of bytes cannot be negative")
+	}
+	if source != "" {
+		path += "/" + string(source)
+	}
+	reqBody, err := json.Marshal(randomRequest{Bytes: n, Format: format})
+	if err != nil {
+		return
+	}
+	req, err := v.requestGen(ctx, http.MethodPost, path, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return
+	}
+	res, err := v.Config.HTTPClient.Do(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+	if err := checkErrorResponse(res); err != nil {
+		return "", err
+	}
+	var response randomResponse
+	err = json.NewDecoder(res.Body).Decode(&response)
+	if err != nil {
+		return "", err
+	}
+	return response.Data.RandomBytes, nil
+}
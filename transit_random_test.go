@@ -0,0 +1,27 @@
+package forest
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_GenerateRandom(t *testing.T) {
+	random, err := GenerateRandom(context.TODO(), 16, FormatHex, "")
+	require.NoError(t, err)
+	b, err := hex.DecodeString(random)
+	require.NoError(t, err)
+	assert.Len(t, b, 16)
+}
+
+func Test_TransitRandom(t *testing.T) {
+	random, err := TransitRandom(context.TODO(), 0, FormatBase64, RandomSourcePlatform)
+	require.NoError(t, err)
+	b, err := base64.StdEncoding.DecodeString(random)
+	require.NoError(t, err)
+	assert.Len(t, b, 32)
+}